@@ -0,0 +1,15 @@
+// Example of reading the content of a workspace using the admin API key to look up its credentials
+data "structurizr_workspace_content" "example" {
+  id = 1
+}
+// Example of reading the content of an encrypted workspace with its own credentials
+data "structurizr_workspace_content" "example_with_encryption" {
+  id         = 2
+  api_key    = "691e0542-5c4d-4f74-be4a-38134a0aa0bf"
+  api_secret = "8497f68e-75b9-431b-b067-cf86a074205c"
+  passphrase = "structurizr"
+}
+
+output "software_systems" {
+  value = jsondecode(data.structurizr_workspace_content.example.json).model.softwareSystems
+}
@@ -0,0 +1,5 @@
+provider "structurizr" {
+  host          = "http://localhost:8080"
+  admin_api_key = "structurizr"
+  tls_insecure  = true
+}
\ No newline at end of file
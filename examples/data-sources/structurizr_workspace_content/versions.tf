@@ -0,0 +1,8 @@
+terraform {
+  required_providers {
+    structurizr = {
+      source  = "fstaoe/structurizr"
+      version = "0.2.0"
+    }
+  }
+}
\ No newline at end of file
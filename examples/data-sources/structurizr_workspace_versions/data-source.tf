@@ -0,0 +1,8 @@
+// Example of listing the versions of a workspace using the admin API key to look up its credentials
+data "structurizr_workspace_versions" "example" {
+  id = 1
+}
+
+output "latest_version" {
+  value = data.structurizr_workspace_versions.example.versions[0].version_id
+}
@@ -0,0 +1,4 @@
+// Example of a checksum covering the files included by the source, to trigger another resource when any changes
+resource "terraform_data" "docs" {
+  triggers_replace = provider::structurizr::workspace_checksum("source/workspace.dsl")
+}
@@ -2,4 +2,29 @@ provider "structurizr" {
   host          = "http://localhost:8080"
   admin_api_key = "structurizr"
   tls_insecure  = true
-}
\ No newline at end of file
+}
+// Example of a Structurizr instance behind an internal PKI requiring mutual TLS, reached through a proxy
+provider "structurizr" {
+  alias              = "internal"
+  host               = "https://structurizr.internal"
+  admin_api_key      = "structurizr"
+  ca_bundle          = "/etc/pki/internal-ca.pem"
+  client_certificate = file("certs/terraform.pem")
+  client_key         = file("certs/terraform-key.pem")
+  proxy_url          = "http://proxy.internal:3128"
+  no_proxy           = "localhost,.svc.cluster.local"
+}
+// Example of an admin API key read from a secret mounted by Vault Agent, rather than from HCL or environment variables
+provider "structurizr" {
+  alias              = "vault"
+  host               = "https://structurizr.internal"
+  admin_api_key_file = "/vault/secrets/structurizr-admin-api-key"
+}
+// Example of a CI pipeline keeping the manual layout of the diagrams, with the archives out of its workspace
+provider "structurizr" {
+  alias             = "ci"
+  host              = "https://structurizr.internal"
+  preserve_layout   = true
+  archive_dir       = "/var/cache/structurizr/archives"
+  archive_retention = 5
+}
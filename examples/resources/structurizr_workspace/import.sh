@@ -1,2 +1,8 @@
 # Example of importing an existing workspace from the remove server
 terraform import structurizr_workspace.example 1
+
+# Example of importing an existing workspace by its name, which must be unique on the remote server
+terraform import structurizr_workspace.example "name:Billing"
+
+# Example of importing an existing workspace encrypted on the client-side with its passphrase
+terraform import structurizr_workspace.example "1:structurizr"
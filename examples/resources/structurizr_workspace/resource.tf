@@ -15,4 +15,50 @@ resource "structurizr_workspace" "example_with_encryption" {
   source            = abspath("source/workspace2encrypt.dsl")
   source_checksum   = md5(file("source/workspace2encrypt.dsl"))
   source_passphrase = "structurizr"
-}
\ No newline at end of file
+}
+// Example of a managed workspace rolled back to a previous version after a bad push
+resource "structurizr_workspace" "example_with_pinned_version" {
+  source          = abspath("source/workspace.dsl")
+  source_checksum = md5(file("source/workspace.dsl"))
+  pinned_version  = "20240501100000000"
+}
+// Example of a managed workspace with a large source taking longer than the default timeouts to push
+resource "structurizr_workspace" "example_with_timeouts" {
+  source          = abspath("source/workspace.dsl")
+  source_checksum = md5(file("source/workspace.dsl"))
+
+  timeouts {
+    create = "45m"
+    update = "45m"
+  }
+}
+// Example of a managed workspace with its DSL generated by Terraform, without writing it to disk
+resource "structurizr_workspace" "example_with_content" {
+  source_content = templatefile("source/workspace.dsl.tftpl", { systems = ["Billing", "Shipping"] })
+}
+// Example of a managed workspace with its JSON generated by Terraform
+resource "structurizr_workspace" "example_with_json_content" {
+  source_content = jsonencode({ name = "Generated", description = "Generated by Terraform", model = {}, views = {} })
+  source_format  = "json"
+}
+// Example of a managed workspace pushed again whenever its source or any file it includes changes
+resource "structurizr_workspace" "example_with_includes" {
+  source = abspath("source/workspace.dsl")
+}
+// Example of a managed workspace keeping the layout of its diagrams made in the Structurizr UI, without archive
+resource "structurizr_workspace" "example_with_layout" {
+  source          = abspath("source/workspace.dsl")
+  preserve_layout = true
+  archive         = false
+}
+// Example of a managed workspace provisioned empty with its name and description, without source
+resource "structurizr_workspace" "example_named" {
+  name        = "Billing"
+  description = "Architecture of the billing platform"
+}
+// Example of a managed workspace protected from destroy, and archived locally when the protection is lifted
+resource "structurizr_workspace" "example_protected" {
+  source              = abspath("source/workspace.dsl")
+  deletion_protection = true
+  archive_on_destroy  = abspath("archives")
+}
@@ -0,0 +1,2 @@
+# Example of importing the content of an existing workspace using its identifier, API key and API secret
+terraform import structurizr_workspace_content.example "12345:${WORKSPACE_API_KEY}:${WORKSPACE_API_SECRET}"
@@ -0,0 +1,4 @@
+// Example of the Structurizr cloud service, which has no admin API
+provider "structurizr" {
+  host = "https://api.structurizr.com"
+}
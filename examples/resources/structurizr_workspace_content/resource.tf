@@ -0,0 +1,18 @@
+variable "workspace_api_key" {
+  type      = string
+  sensitive = true
+}
+
+variable "workspace_api_secret" {
+  type      = string
+  sensitive = true
+}
+
+// Example of the content of an existing workspace pushed from its source
+resource "structurizr_workspace_content" "example" {
+  id              = 12345
+  api_key         = var.workspace_api_key
+  api_secret      = var.workspace_api_secret
+  source          = abspath("../structurizr_workspace/source/workspace.dsl")
+  source_checksum = md5(file("../structurizr_workspace/source/workspace.dsl"))
+}
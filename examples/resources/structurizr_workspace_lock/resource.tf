@@ -0,0 +1,11 @@
+resource "structurizr_workspace" "example" {
+  source          = abspath("../structurizr_workspace/source/workspace.dsl")
+  source_checksum = md5(file("../structurizr_workspace/source/workspace.dsl"))
+}
+// Example of locking a managed workspace so it cannot be edited in the UI
+resource "structurizr_workspace_lock" "example" {
+  workspace_id = structurizr_workspace.example.id
+  api_key      = structurizr_workspace.example.api_key
+  api_secret   = structurizr_workspace.example.api_secret
+  user         = "platform-team"
+}
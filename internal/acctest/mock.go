@@ -113,6 +113,41 @@ const (
       "shareableUrl": ""
     }
   ]
+}`
+	MockResourceWorkspaceDuplicatesGet = `{
+  "workspaces": [
+    {
+      "id": 1,
+      "name": "Workspace 0001",
+      "description": "Description",
+      "apiKey": "691e0542-5c4d-4f74-be4a-38134a0aa0bf",
+      "apiSecret": "8497f68e-75b9-431b-b067-cf86a074205c",
+      "privateUrl": "/workspace/1",
+      "publicUrl": "/share/1",
+      "shareableUrl": ""
+    },
+    {
+      "id": 2,
+      "name": "Workspace 0001",
+      "description": "Description",
+      "apiKey": "0d1c3f2e-7a8b-4c5d-9e6f-a1b2c3d4e5f6",
+      "apiSecret": "f6e5d4c3-b2a1-4f6e-9d8c-7b6a5f4e3d2c",
+      "privateUrl": "/workspace/2",
+      "publicUrl": "/share/2",
+      "shareableUrl": ""
+    }
+  ]
+}`
+	MockResourceWorkspaceBasicContent = `{
+  "id": 1,
+  "name": "Workspace 0001",
+  "description": "Description",
+  "revision": 1,
+  "lastModifiedDate": "2024-05-01T09:00:00Z",
+  "lastModifiedUser": "",
+  "lastModifiedAgent": "structurizr-onpremises",
+  "model": {},
+  "views": {}
 }`
 	MockResourceWorkspaceBasicDelete = `{
   "success": true,
@@ -137,5 +172,72 @@ const (
       "shareableUrl": ""
     }
   ]
+}`
+	MockDataSourceWorkspaceContentBasic = `{
+  "id": 1,
+  "name": "Workspace JSON",
+  "description": "Managed Workspace by JSON",
+  "revision": 3,
+  "lastModifiedDate": "2024-05-01T10:00:00Z",
+  "lastModifiedUser": "admin",
+  "lastModifiedAgent": "structurizr-cli/2024.03.03",
+  "model": {},
+  "views": {}
+}`
+	MockDataSourceWorkspaceContentEncrypted = `{
+  "id": 1,
+  "name": "Encrypted Workspace",
+  "description": "",
+  "revision": 4,
+  "lastModifiedDate": "2024-05-01T10:00:00Z",
+  "lastModifiedUser": "",
+  "lastModifiedAgent": "structurizr-cli/2024.03.03",
+  "ciphertext": "37Q2zFesbKH6PzIwrn/6nvL+lDUxWCRQLgkDiJFRW1J+Ub2+S1lsfUxoxBt8ou99OVM9Rm3Q/1iXKHmZbnr8Gw==",
+  "encryptionStrategy": {
+    "type": "aes",
+    "location": "Client",
+    "keySize": 128,
+    "iterationCount": 1000,
+    "salt": "8f2cb3a0e34b5a3c1b1e0d3c5e6f7a81",
+    "iv": "00112233445566778899aabbccddeeff"
+  }
+}`
+	MockResourceWorkspaceLock = `{
+  "success": true,
+  "message": "OK"
+}`
+	MockResourceWorkspaceWithSourceContent = `{
+  "id": 1,
+  "name": "Workspace DSL",
+  "description": "Managed Workspace by DSL",
+  "revision": 2,
+  "lastModifiedDate": "2024-05-01T10:00:00Z",
+  "lastModifiedUser": "",
+  "lastModifiedAgent": "structurizr-cli/2024.03.03",
+  "model": {},
+  "views": {}
+}`
+	MockDataSourceWorkspaceVersions = `{
+  "versions": [
+    {
+      "versionId": "20240502090000000",
+      "timestamp": "2024-05-02T09:00:00Z"
+    },
+    {
+      "versionId": "20240501100000000",
+      "timestamp": "2024-05-01T10:00:00Z"
+    }
+  ]
+}`
+	MockResourceWorkspaceVersionContent = `{
+  "id": 1,
+  "name": "Workspace DSL",
+  "description": "Managed Workspace by DSL",
+  "revision": 1,
+  "lastModifiedDate": "2024-05-01T10:00:00Z",
+  "lastModifiedUser": "",
+  "lastModifiedAgent": "structurizr-cli/2024.03.03",
+  "model": {},
+  "views": {}
 }`
 )
@@ -3,30 +3,54 @@ package api
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/retry"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/transport"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/crypto"
 	"github.com/fstaoe/terraform-provider-structurizr/version"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const (
 	DefaultUserAgent                 = "go-structurizr/" + version.LibraryVersion
 	workspaceListCreateTemplate      = "/api/workspace"
 	workspaceGetUpdateDeleteTemplate = "/api/workspace/%s"
+	workspaceLockUnlockTemplate      = "/api/workspace/%s/lock"
+	workspaceVersionsTemplate        = "/api/workspace/%s/versions"
+	// DefaultTimeout is the default upper bound of a single request
+	DefaultTimeout = 1 * time.Minute
+	// CloudServiceHost is the host of the Structurizr cloud service API, which is served without the /api prefix
+	CloudServiceHost = "api.structurizr.com"
+	apiBasePath      = "/api"
 )
 
+// ErrMissingAdminAPIKey is returned by the operations of the admin API when no admin API key is configured,
+// such as with the Structurizr cloud service which has no admin API
+var ErrMissingAdminAPIKey = errors.New("admin API key is not configured")
+
+// random is the source of the salt and initialisation vector of encrypted workspaces
+var random io.Reader = rand.Reader
+
 // Config is the primary means to modify the Client
 type Config struct {
 	AdminAPIKey string
 	BaseURL     *url.URL
-	TLSInsecure bool
+	Transport   transport.Config
 	UserAgent   string
+	Retry       retry.Policy
+	// Timeout bounds every request, retries excluded. Zero means no timeout.
+	Timeout time.Duration
 }
 
 // Client is the main Client API interface.
@@ -42,8 +66,14 @@ type Doer interface {
 }
 
 // NewClient returns an API Client used to communicate with the remote server
-func NewClient(config *Config) *Client {
-	return &Client{config, newHTTPClient(config.TLSInsecure)}
+func NewClient(config *Config) (*Client, error) {
+	httpClient, err := newHTTPClient(config.Transport)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Timeout = config.Timeout
+
+	return &Client{config, httpClient}, nil
 }
 
 // GetWorkspaces lists all workspaces
@@ -77,17 +107,198 @@ func (c *Client) DeleteWorkspace(ctx context.Context, id int64) (*model.APIRespo
 	return res.(*model.APIResponse), err
 }
 
-// newHTTPClient return an HTTP client configure TLS configuration for high customisation
-func newHTTPClient(insecureSkipVerify bool) *http.Client {
+// GetWorkspace retrieves the content of a workspace using its API key and secret
+func (c *Client) GetWorkspace(ctx context.Context, id int64, key string, secret string) (*model.WorkspaceContent, error) {
+	u := urlEncodeTemplate(workspaceGetUpdateDeleteTemplate, strconv.FormatInt(id, 10))
+	return c.getWorkspace(ctx, u, id, key, secret)
+}
+
+// GetWorkspaceVersion retrieves the content of a previous version of a workspace using its API key and secret
+func (c *Client) GetWorkspaceVersion(
+	ctx context.Context,
+	id int64,
+	key string,
+	secret string,
+	version string,
+) (*model.WorkspaceContent, error) {
+	query := url.Values{}
+	query.Set("version", version)
+
+	u := urlEncodeTemplate(workspaceGetUpdateDeleteTemplate, strconv.FormatInt(id, 10)) + "?" + query.Encode()
+	return c.getWorkspace(ctx, u, id, key, secret)
+}
+
+// GetWorkspaceVersions lists the previous versions of a workspace kept by the remote server
+func (c *Client) GetWorkspaceVersions(
+	ctx context.Context,
+	id int64,
+	key string,
+	secret string,
+) (*model.WorkspaceVersions, error) {
+	u := urlEncodeTemplate(workspaceVersionsTemplate, strconv.FormatInt(id, 10))
+	res, err := c.doSigned(ctx, http.MethodGet, u, key, secret, nil, new(model.WorkspaceVersions))
+	return res.(*model.WorkspaceVersions), err
+}
+
+// RestoreWorkspaceVersion pushes a previous version of a workspace as its latest version.
+// Encrypted versions are restored as they are, without being decrypted.
+func (c *Client) RestoreWorkspaceVersion(ctx context.Context, id int64, key string, secret string, version string) error {
+	previous, err := c.GetWorkspaceVersion(ctx, id, key, secret, version)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve version %s: %w", version, err)
+	}
+
+	current, err := c.GetWorkspace(ctx, id, key, secret)
+	if err != nil {
+		return err
+	}
+
+	workspace := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(previous.Raw, &workspace); err != nil {
+		return fmt.Errorf("failed decoding workspace (id: %d) version %s with: %w", id, version, err)
+	}
+
+	// The restored version is pushed on top of the current revision so the remote server accepts it
+	workspace["id"], _ = json.Marshal(id)
+	workspace["revision"], _ = json.Marshal(current.Revision)
+	workspace["lastModifiedAgent"], _ = json.Marshal(c.config.UserAgent)
+
+	body, err := json.Marshal(workspace)
+	if err != nil {
+		return err
+	}
+
+	u := urlEncodeTemplate(workspaceGetUpdateDeleteTemplate, strconv.FormatInt(id, 10))
+	_, err = c.doSigned(ctx, http.MethodPut, u, key, secret, body, new(model.APIResponse))
+	return err
+}
+
+// getWorkspace retrieves the content of a workspace from the given path
+func (c *Client) getWorkspace(ctx context.Context, u string, id int64, key string, secret string) (*model.WorkspaceContent, error) {
+	res, err := c.doSigned(ctx, http.MethodGet, u, key, secret, nil, new(json.RawMessage))
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *res.(*json.RawMessage)
+	content := &model.WorkspaceContent{Raw: raw}
+	if err = json.Unmarshal(raw, content); err != nil {
+		return nil, fmt.Errorf("failed decoding workspace (id: %d) with: %w", id, err)
+	}
+
+	return content, nil
+}
+
+// LockWorkspace locks a workspace on behalf of the given user and agent
+func (c *Client) LockWorkspace(
+	ctx context.Context,
+	id int64,
+	key string,
+	secret string,
+	user string,
+	agent string,
+) (*model.APIResponse, error) {
+	return c.doLock(ctx, http.MethodPut, id, key, secret, user, agent)
+}
+
+// UnlockWorkspace unlocks a workspace previously locked by the given user and agent
+func (c *Client) UnlockWorkspace(
+	ctx context.Context,
+	id int64,
+	key string,
+	secret string,
+	user string,
+	agent string,
+) (*model.APIResponse, error) {
+	return c.doLock(ctx, http.MethodDelete, id, key, secret, user, agent)
+}
+
+// PushWorkspace push a new version of a workspace from an existing JSON file.
+// The previous version of the workspace is archived first when requested, its layout cannot be merged.
+func (c *Client) PushWorkspace(
+	ctx context.Context,
+	id int64,
+	key string,
+	secret string,
+	passphrase string,
+	source string,
+	options PushOptions,
+) error {
+	if options.Merge {
+		return ErrMergeUnsupported
+	}
+
+	body, err := readWorkspaceSource(source, id, c.config.UserAgent)
+	if err != nil {
+		return err
+	}
+
+	if options.Archive {
+		previous, err := c.GetWorkspace(ctx, id, key, secret)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve workspace (id: %d) to archive: %w", id, err)
+		}
+
+		if err = WriteArchive(options.ArchiveDir, id, previous.Raw); err != nil {
+			return err
+		}
+	}
+
+	if passphrase != "" {
+		strategy, err := crypto.NewAESStrategy(crypto.DefaultKeySize, crypto.DefaultIterationCount, random)
+		if err != nil {
+			return err
+		}
+
+		if body, err = crypto.Seal(body, passphrase, strategy); err != nil {
+			return fmt.Errorf("failed to encrypt workspace (id: %d): %w", id, err)
+		}
+	}
+
+	u := urlEncodeTemplate(workspaceGetUpdateDeleteTemplate, strconv.FormatInt(id, 10))
+	_, err = c.doSigned(ctx, http.MethodPut, u, key, secret, body, new(model.APIResponse))
+	return err
+}
+
+// readWorkspaceSource reads a JSON workspace from the disk and prepares it to be pushed to the workspace with the given ID
+func readWorkspaceSource(source string, id int64, agent string) ([]byte, error) {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace source: %w", err)
+	}
+
+	workspace := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &workspace); err != nil {
+		return nil, fmt.Errorf(
+			"workspace source %s is not a valid JSON workspace, DSL sources require the CLI push client: %w",
+			source,
+			err,
+		)
+	}
+
+	workspace["id"], _ = json.Marshal(id)
+	workspace["lastModifiedAgent"], _ = json.Marshal(agent)
+
+	return json.Marshal(workspace)
+}
+
+// newHTTPClient return an HTTP client configure TLS and proxy configuration for high customisation
+func newHTTPClient(config transport.Config) (*http.Client, error) {
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	// Prevent issues with multiple data source configurations modifying the shared transport.
 	tr := http.DefaultTransport.(*http.Transport).Clone()
-	tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	tr.TLSClientConfig = tlsConfig
+	tr.Proxy = config.Proxy()
 
-	return &http.Client{Transport: tr}
+	return &http.Client{Transport: tr}, nil
 }
 
 func (c *Client) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
-	rel := &url.URL{Path: path}
+	rel := &url.URL{Path: c.apiPath(path)}
 	u := c.config.BaseURL.ResolveReference(rel)
 	var buf = new(bytes.Buffer)
 	if body != nil {
@@ -116,8 +327,94 @@ func (c *Client) newRequest(ctx context.Context, method, path string, body inter
 	return req, nil
 }
 
-func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.doer.Do(req)
+// newSignedRequest creates a request authenticated with the workspace API key and secret
+func (c *Client) newSignedRequest(
+	ctx context.Context,
+	method string,
+	path string,
+	key string,
+	secret string,
+	body []byte,
+) (*http.Request, error) {
+	rel, err := url.Parse(c.apiPath(path))
+	if err != nil {
+		return nil, err
+	}
+	u := c.config.BaseURL.ResolveReference(rel)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var contentType string
+	if len(body) > 0 {
+		contentType = "application/json; charset=UTF-8"
+		req.Header.Set("Content-Type", contentType)
+		tflog.Trace(ctx, fmt.Sprintf("raw body to be sent over wire: %s", body))
+	}
+
+	s := &signature{
+		Method:      method,
+		Path:        u.RequestURI(),
+		ContentType: contentType,
+		Nonce:       strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Body:        body,
+	}
+	s.sign(req.Header, key, secret)
+
+	req.Header.Set("User-Agent", c.config.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	return req, nil
+}
+
+// requestBuilder builds a new request for every attempt, so bodies and signatures are always fresh
+type requestBuilder func() (*http.Request, error)
+
+// send sends the request built by newReq and retries it on transient failures according to the retry policy
+func (c *Client) send(ctx context.Context, newReq requestBuilder) (*http.Request, *http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resp, err := c.doer.Do(req)
+		if attempt > c.config.Retry.MaxRetries || !isRetryable(req, resp, err) {
+			return req, resp, err
+		}
+
+		wait := c.config.Retry.Backoff(attempt)
+		reason := fmt.Sprintf("%v", err)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok && after > wait {
+				wait = c.config.Retry.Cap(after)
+			}
+			reason = resp.Status
+
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		tflog.Warn(ctx, fmt.Sprintf(
+			"retrying %s %s in %s (attempt %d of %d) after: %s",
+			req.Method,
+			req.URL.Path,
+			wait,
+			attempt,
+			c.config.Retry.MaxRetries,
+			reason,
+		))
+
+		if err = retry.Wait(ctx, wait); err != nil {
+			return req, nil, err
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, newReq requestBuilder, v interface{}) (*http.Response, error) {
+	req, resp, err := c.send(ctx, newReq)
 	if err != nil {
 		return nil, err
 	}
@@ -130,16 +427,8 @@ func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) (*htt
 	}()
 	tflog.Trace(ctx, fmt.Sprintf("request: %+v response: %+v", req, resp))
 
-	if resp.StatusCode >= 500 {
-		return handleError(ctx, model.APIErrSystemUnavailable, req, resp)
-	}
-
-	if resp.StatusCode == 401 {
-		return handleError(ctx, model.APIErrUnauthorized, req, resp)
-	}
-
-	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-		return handleError(ctx, model.APIErrBadRequest, req, resp)
+	if resp.StatusCode >= 400 {
+		return handleError(ctx, model.ErrorForStatus(resp.StatusCode), req, resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -148,6 +437,19 @@ func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) (*htt
 	}
 	tflog.Debug(ctx, fmt.Sprintf("Received API response: %s", body))
 
+	if v != nil && isHTML(resp, body) {
+		return resp, &model.APIErrorResponse{
+			Message: fmt.Sprintf(
+				"expected JSON from %s %s but received an HTML page, a reverse proxy or an SSO gateway may "+
+					"have answered instead of Structurizr",
+				req.Method,
+				req.URL.Path,
+			),
+			StatusCode: resp.StatusCode,
+			Err:        model.APIErrUnexpectedContent,
+		}
+	}
+
 	if v != nil {
 		err = json.NewDecoder(io.NopCloser(bytes.NewBuffer(body))).Decode(v)
 		if err != nil {
@@ -166,15 +468,21 @@ func (c *Client) doCrud(
 	requestEntity interface{},
 	responseEntity interface{},
 ) (interface{}, error) {
-	var resp *http.Response
+	var (
+		resp *http.Response
+		err  error
+	)
 
-	req, err := c.newRequest(ctx, method, path, requestEntity)
-	if err != nil {
-		return responseEntity, err
+	if c.config.AdminAPIKey == "" {
+		return responseEntity, ErrMissingAdminAPIKey
+	}
+
+	newReq := func() (*http.Request, error) {
+		return c.newRequest(ctx, method, path, requestEntity)
 	}
 
 	if responseEntity == nil {
-		if resp, err = c.do(ctx, req, nil); err == nil {
+		if resp, err = c.do(ctx, newReq, nil); err == nil {
 			// 201 -> extract the location header if the expectation is a string value
 			switch resp.StatusCode {
 			case http.StatusCreated:
@@ -183,18 +491,69 @@ func (c *Client) doCrud(
 			}
 		}
 	} else {
-		_, err = c.do(ctx, req, &responseEntity)
+		_, err = c.do(ctx, newReq, &responseEntity)
 	}
 
 	return responseEntity, err
 }
 
+func (c *Client) doSigned(
+	ctx context.Context,
+	method string,
+	path string,
+	key string,
+	secret string,
+	body []byte,
+	responseEntity interface{},
+) (interface{}, error) {
+	newReq := func() (*http.Request, error) {
+		return c.newSignedRequest(ctx, method, path, key, secret, body)
+	}
+
+	_, err := c.do(ctx, newReq, &responseEntity)
+
+	return responseEntity, err
+}
+
+func (c *Client) doLock(
+	ctx context.Context,
+	method string,
+	id int64,
+	key string,
+	secret string,
+	user string,
+	agent string,
+) (*model.APIResponse, error) {
+	query := url.Values{}
+	query.Set("user", user)
+	query.Set("agent", agent)
+
+	u := urlEncodeTemplate(workspaceLockUnlockTemplate, strconv.FormatInt(id, 10)) + "?" + query.Encode()
+	res, err := c.doSigned(ctx, method, u, key, secret, nil, new(model.APIResponse))
+	apiResponse := res.(*model.APIResponse)
+	if err != nil {
+		return apiResponse, err
+	}
+
+	// Structurizr answers with a successful HTTP status even when the lock is held by someone else
+	if !apiResponse.Success {
+		return apiResponse, &model.APIErrorResponse{Message: apiResponse.Message, Err: model.APIErrConflict}
+	}
+
+	return apiResponse, nil
+}
+
 func handleError(ctx context.Context, err error, req *http.Request, resp *http.Response) (*http.Response, error) {
 	bodyBytes, _ := io.ReadAll(resp.Body)
 	_ = resp.Body.Close() //  must close
 	tflog.Debug(ctx, fmt.Sprintf("handling error response: %s", string(bodyBytes)))
 
-	e := &model.APIErrorResponse{Err: err}
+	e := &model.APIErrorResponse{StatusCode: resp.StatusCode, Err: err}
+	if isHTML(resp, bodyBytes) {
+		e.Message = fmt.Sprintf("received an HTML page with status %d instead of a JSON error", resp.StatusCode)
+		return resp, e
+	}
+
 	decodingErr := json.NewDecoder(bytes.NewBuffer(bodyBytes)).Decode(e)
 	if decodingErr != nil {
 		tflog.Debug(
@@ -211,6 +570,71 @@ func handleError(ctx context.Context, err error, req *http.Request, resp *http.R
 	return resp, e
 }
 
+// isHTML reports whether a response is an HTML page rather than the JSON served by Structurizr
+func isHTML(resp *http.Response, body []byte) bool {
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return true
+	}
+
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<"))
+}
+
+// isRetryable reports whether a request can be retried given its outcome. Connection failures and server errors
+// are only retried for idempotent methods, whereas rate-limited requests have never been processed by the server.
+func isRetryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	idempotent := req.Method == http.MethodGet ||
+		req.Method == http.MethodHead ||
+		req.Method == http.MethodOptions ||
+		req.Method == http.MethodPut ||
+		req.Method == http.MethodDelete
+
+	if err != nil {
+		return idempotent
+	}
+
+	return idempotent && resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+// retryAfter parses the Retry-After header which is either a number of seconds or an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(v); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// apiPath returns the path of an API endpoint on the remote server, the cloud service serves them without the /api prefix
+func (c *Client) apiPath(path string) string {
+	if IsCloudService(c.config.BaseURL) {
+		return strings.TrimPrefix(path, apiBasePath)
+	}
+
+	return path
+}
+
+// IsCloudService reports whether the base URL targets the Structurizr cloud service rather than an on-premises installation
+func IsCloudService(baseURL *url.URL) bool {
+	return baseURL != nil && strings.EqualFold(baseURL.Hostname(), CloudServiceHost)
+}
+
 func urlEncodeTemplate(template string, parameters ...string) string {
 	encodedParams := make([]interface{}, len(parameters))
 
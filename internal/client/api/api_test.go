@@ -4,13 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/retry"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/transport"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 // MockHTTPClient is a mock for the HTTP doer
@@ -25,21 +33,24 @@ func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
 
 func TestNewClient_InsecureSkipVerify(t *testing.T) {
 	t.Run("Given disabled TLS verification", func(t *testing.T) {
-		client := NewClient(&Config{TLSInsecure: true})
+		client, err := NewClient(&Config{Transport: transport.Config{TLSInsecure: true}})
+		assert.NoError(t, err)
 		transport := client.doer.(*http.Client).Transport.(*http.Transport)
 		assert.NotNil(t, transport)
 		assert.NotNil(t, transport.TLSClientConfig)
 		assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
 	})
 	t.Run("Given enabled TLS verification", func(t *testing.T) {
-		client := NewClient(&Config{TLSInsecure: false})
+		client, err := NewClient(&Config{Transport: transport.Config{TLSInsecure: false}})
+		assert.NoError(t, err)
 		transport := client.doer.(*http.Client).Transport.(*http.Transport)
 		assert.NotNil(t, transport)
 		assert.NotNil(t, transport.TLSClientConfig)
 		assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
 	})
 	t.Run("Given default TLS verification", func(t *testing.T) {
-		client := NewClient(&Config{})
+		client, err := NewClient(&Config{})
+		assert.NoError(t, err)
 		transport := client.doer.(*http.Client).Transport.(*http.Transport)
 		assert.NotNil(t, transport)
 		assert.NotNil(t, transport.TLSClientConfig)
@@ -47,6 +58,31 @@ func TestNewClient_InsecureSkipVerify(t *testing.T) {
 	})
 }
 
+func TestNewClient_Transport(t *testing.T) {
+	t.Run("Given a proxy", func(t *testing.T) {
+		proxyURL, _ := url.Parse("http://proxy.internal:3128")
+		client, err := NewClient(&Config{Transport: transport.Config{ProxyURL: proxyURL}})
+		assert.NoError(t, err)
+
+		req, _ := http.NewRequest(http.MethodGet, "https://structurizr.example.com/api/workspace", nil)
+		actual, err := client.doer.(*http.Client).Transport.(*http.Transport).Proxy(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, proxyURL, actual)
+	})
+	t.Run("Given an invalid CA bundle", func(t *testing.T) {
+		_, err := NewClient(&Config{Transport: transport.Config{CABundle: []byte("not a certificate")}})
+
+		assert.Error(t, err)
+	})
+	t.Run("Given a timeout", func(t *testing.T) {
+		client, err := NewClient(&Config{Timeout: 30 * time.Second})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 30*time.Second, client.doer.(*http.Client).Timeout)
+	})
+}
+
 // TestGetWorkspaces tests the GetWorkspaces function
 func TestGetWorkspaces(t *testing.T) {
 	config := &Config{
@@ -146,3 +182,497 @@ func TestDeleteWorkspace(t *testing.T) {
 	assert.NotNil(t, apiResponse)
 	assert.Equal(t, "Deleted", apiResponse.Message)
 }
+
+// TestPushWorkspace tests the PushWorkspace function
+func TestPushWorkspace(t *testing.T) {
+	config := &Config{
+		BaseURL:   &url.URL{Scheme: "http", Host: "localhost:8080"},
+		UserAgent: "test-agent",
+	}
+
+	source := filepath.Join(t.TempDir(), "workspace.json")
+	err := os.WriteFile(source, []byte(`{"name":"Workspace JSON","model":{}}`), 0600)
+	assert.NoError(t, err)
+
+	t.Run("Given a JSON source", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{config, mockClient}
+
+		body, _ := json.Marshal(&model.APIResponse{Success: true, Message: "OK", Revision: 2})
+		resp := &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBuffer(body)),
+		}
+
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			sent, _ := io.ReadAll(req.Body)
+			return req.Method == http.MethodPut &&
+				req.URL.Path == "/api/workspace/1" &&
+				strings.HasPrefix(req.Header.Get("X-Authorization"), "key:") &&
+				req.Header.Get("Nonce") != "" &&
+				req.Header.Get("Content-MD5") != "" &&
+				string(sent) == `{"id":1,"lastModifiedAgent":"test-agent","model":{},"name":"Workspace JSON"}`
+		})).Return(resp, nil)
+
+		err := client.PushWorkspace(context.Background(), 1, "key", "secret", "", source, PushOptions{})
+
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+	t.Run("Given a DSL source", func(t *testing.T) {
+		dsl := filepath.Join(t.TempDir(), "workspace.dsl")
+		err := os.WriteFile(dsl, []byte(`workspace {}`), 0600)
+		assert.NoError(t, err)
+
+		client := &Client{config, new(MockHTTPClient)}
+
+		err = client.PushWorkspace(context.Background(), 1, "key", "secret", "", dsl, PushOptions{})
+
+		assert.ErrorContains(t, err, "DSL sources require the CLI push client")
+	})
+	t.Run("Given archiving", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{config, mockClient}
+		archiveDir := filepath.Join(t.TempDir(), "archives")
+
+		previous := `{"id":1,"name":"Previous","revision":1}`
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Method == http.MethodGet && req.URL.Path == "/api/workspace/1"
+		})).Return(&http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(previous))}, nil).Once()
+
+		body, _ := json.Marshal(&model.APIResponse{Success: true, Message: "OK", Revision: 2})
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Method == http.MethodPut && req.URL.Path == "/api/workspace/1"
+		})).Return(&http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewBuffer(body))}, nil).Once()
+
+		err := client.PushWorkspace(
+			context.Background(),
+			1,
+			"key",
+			"secret",
+			"",
+			source,
+			PushOptions{Archive: true, ArchiveDir: archiveDir},
+		)
+
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+
+		archives, _ := filepath.Glob(filepath.Join(archiveDir, "structurizr-1-*.json"))
+		if assert.Len(t, archives, 1) {
+			data, _ := os.ReadFile(archives[0])
+			assert.JSONEq(t, previous, string(data))
+		}
+	})
+	t.Run("Given preserving the layout", func(t *testing.T) {
+		client := &Client{config, new(MockHTTPClient)}
+
+		err := client.PushWorkspace(context.Background(), 1, "key", "secret", "", source, PushOptions{Merge: true})
+
+		assert.ErrorIs(t, err, ErrMergeUnsupported)
+	})
+	t.Run("Given a passphrase", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{config, mockClient}
+
+		body, _ := json.Marshal(&model.APIResponse{Success: true, Message: "OK", Revision: 2})
+		resp := &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBuffer(body)),
+		}
+
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			sent, _ := io.ReadAll(req.Body)
+			workspace, err := crypto.Open(sent, "passphrase")
+			return err == nil &&
+				!strings.Contains(string(sent), `"model"`) &&
+				string(workspace) == `{"id":1,"lastModifiedAgent":"test-agent","model":{},"name":"Workspace JSON"}`
+		})).Return(resp, nil)
+
+		err := client.PushWorkspace(context.Background(), 1, "key", "secret", "passphrase", source, PushOptions{})
+
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+// TestGetWorkspace tests the GetWorkspace function
+func TestGetWorkspace(t *testing.T) {
+	config := &Config{
+		BaseURL:   &url.URL{Scheme: "http", Host: "localhost:8080"},
+		UserAgent: "test-agent",
+	}
+
+	mockClient := new(MockHTTPClient)
+	client := &Client{config, mockClient}
+
+	body := `{"id":1,"name":"Test Workspace","revision":3,"lastModifiedAgent":"structurizr-cli","model":{}}`
+	resp := &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			req.URL.Path == "/api/workspace/1" &&
+			strings.HasPrefix(req.Header.Get("X-Authorization"), "key:")
+	})).Return(resp, nil)
+
+	content, err := client.GetWorkspace(context.Background(), 1, "key", "secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), content.ID)
+	assert.Equal(t, "Test Workspace", content.Name)
+	assert.Equal(t, int64(3), content.Revision)
+	assert.Equal(t, "structurizr-cli", content.LastModifiedAgent)
+	assert.False(t, content.IsEncrypted())
+	assert.JSONEq(t, body, string(content.Raw))
+}
+
+// TestGetWorkspaceVersions tests the GetWorkspaceVersions function
+func TestGetWorkspaceVersions(t *testing.T) {
+	config := &Config{
+		BaseURL:   &url.URL{Scheme: "http", Host: "localhost:8080"},
+		UserAgent: "test-agent",
+	}
+
+	mockClient := new(MockHTTPClient)
+	client := &Client{config, mockClient}
+
+	body := `{"versions":[{"versionId":"20240502090000000","timestamp":"2024-05-02T09:00:00Z"},` +
+		`{"versionId":"20240501100000000","timestamp":"2024-05-01T10:00:00Z"}]}`
+	resp := &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet &&
+			req.URL.Path == "/api/workspace/1/versions" &&
+			strings.HasPrefix(req.Header.Get("X-Authorization"), "key:")
+	})).Return(resp, nil)
+
+	versions, err := client.GetWorkspaceVersions(context.Background(), 1, "key", "secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(versions.Versions))
+	assert.Equal(t, "20240502090000000", versions.Versions[0].VersionID)
+	assert.Equal(t, "2024-05-02T09:00:00Z", versions.Versions[0].Timestamp)
+	assert.NotNil(t, versions.FindByID("20240501100000000"))
+	assert.Nil(t, versions.FindByID("unknown"))
+}
+
+// TestRestoreWorkspaceVersion tests the RestoreWorkspaceVersion function
+func TestCloudService(t *testing.T) {
+	config := &Config{
+		BaseURL:   &url.URL{Scheme: "https", Host: CloudServiceHost},
+		UserAgent: "test-agent",
+	}
+
+	t.Run("Given a workspace API request", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{config, mockClient}
+
+		resp := &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"id":1,"name":"Cloud","revision":3}`)),
+		}
+
+		mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Method == http.MethodGet &&
+				req.URL.String() == "https://api.structurizr.com/workspace/1" &&
+				strings.HasPrefix(req.Header.Get("X-Authorization"), "key:")
+		})).Return(resp, nil)
+
+		workspace, err := client.GetWorkspace(context.Background(), 1, "key", "secret")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), workspace.Revision)
+	})
+	t.Run("Given an admin API request without admin API key", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{config, mockClient}
+
+		_, err := client.GetWorkspaces(context.Background())
+
+		assert.ErrorIs(t, err, ErrMissingAdminAPIKey)
+		mockClient.AssertNotCalled(t, "Do", mock.Anything)
+	})
+}
+
+func TestIsCloudService(t *testing.T) {
+	tests := []struct {
+		baseURL  string
+		expected bool
+	}{
+		{"https://api.structurizr.com", true},
+		{"https://API.structurizr.com:443", true},
+		{"https://structurizr.com", false},
+		{"http://localhost:8080", false},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.baseURL)
+		assert.Equal(t, tt.expected, IsCloudService(u), tt.baseURL)
+	}
+}
+
+func TestRestoreWorkspaceVersion(t *testing.T) {
+	config := &Config{
+		BaseURL:   &url.URL{Scheme: "http", Host: "localhost:8080"},
+		UserAgent: "test-agent",
+	}
+
+	mockClient := new(MockHTTPClient)
+	client := &Client{config, mockClient}
+
+	newResponse := func(body string) *http.Response {
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewBufferString(body))}
+	}
+
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && req.URL.RequestURI() == "/api/workspace/1?version=20240501100000000"
+	})).Return(newResponse(`{"id":1,"name":"Previous","revision":2,"model":{}}`), nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && req.URL.RequestURI() == "/api/workspace/1"
+	})).Return(newResponse(`{"id":1,"name":"Current","revision":5,"model":{}}`), nil).Once()
+	mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		if req.Method != http.MethodPut || req.URL.Path != "/api/workspace/1" {
+			return false
+		}
+
+		sent, _ := io.ReadAll(req.Body)
+		return string(sent) == `{"id":1,"lastModifiedAgent":"test-agent","model":{},"name":"Previous","revision":5}`
+	})).Return(newResponse(`{"success":true,"message":"OK","revision":6}`), nil).Once()
+
+	err := client.RestoreWorkspaceVersion(context.Background(), 1, "key", "secret", "20240501100000000")
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+// TestLockWorkspace tests the LockWorkspace and UnlockWorkspace functions
+func TestLockWorkspace(t *testing.T) {
+	config := &Config{
+		BaseURL:   &url.URL{Scheme: "http", Host: "localhost:8080"},
+		UserAgent: "test-agent",
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		body     string
+		call     func(c *Client) (*model.APIResponse, error)
+		expected string
+	}{
+		{
+			name:   "Given a workspace to lock",
+			method: http.MethodPut,
+			body:   `{"success":true,"message":"OK"}`,
+			call: func(c *Client) (*model.APIResponse, error) {
+				return c.LockWorkspace(context.Background(), 1, "key", "secret", "terraform", "agent")
+			},
+		},
+		{
+			name:   "Given a workspace to unlock",
+			method: http.MethodDelete,
+			body:   `{"success":true,"message":"OK"}`,
+			call: func(c *Client) (*model.APIResponse, error) {
+				return c.UnlockWorkspace(context.Background(), 1, "key", "secret", "terraform", "agent")
+			},
+		},
+		{
+			name:   "Given a workspace locked by someone else",
+			method: http.MethodPut,
+			body:   `{"success":false,"message":"The workspace is locked by user@example.com"}`,
+			call: func(c *Client) (*model.APIResponse, error) {
+				return c.LockWorkspace(context.Background(), 1, "key", "secret", "terraform", "agent")
+			},
+			expected: "The workspace is locked by user@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockHTTPClient)
+			client := &Client{config, mockClient}
+
+			resp := &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(tt.body)),
+			}
+
+			mockClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.Method == tt.method &&
+					req.URL.RequestURI() == "/api/workspace/1/lock?agent=agent&user=terraform"
+			})).Return(resp, nil)
+
+			_, err := tt.call(client)
+
+			if tt.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expected)
+			}
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// TestRetry tests the retry behaviour on transient failures
+func TestRetry(t *testing.T) {
+	config := &Config{
+		AdminAPIKey: "test-key",
+		BaseURL:     &url.URL{Scheme: "http", Host: "localhost:8080"},
+		UserAgent:   "test-agent",
+		Retry:       retry.Policy{MaxRetries: 2, MinWait: time.Millisecond, MaxWait: 5 * time.Millisecond},
+	}
+
+	newResponse := func(statusCode int, body string) *http.Response {
+		return &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Header:     http.Header{"Retry-After": []string{"0"}},
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}
+	}
+
+	t.Run("Given a server error on an idempotent request", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{config, mockClient}
+
+		mockClient.On("Do", mock.Anything).Return(newResponse(http.StatusServiceUnavailable, ""), nil).Once()
+		mockClient.On("Do", mock.Anything).Return(newResponse(http.StatusOK, `{"workspaces":[]}`), nil).Once()
+
+		_, err := client.GetWorkspaces(context.Background())
+
+		assert.NoError(t, err)
+		mockClient.AssertNumberOfCalls(t, "Do", 2)
+	})
+	t.Run("Given a connection error on an idempotent request", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{config, mockClient}
+
+		mockClient.On("Do", mock.Anything).Return((*http.Response)(nil), errors.New("connection refused")).Once()
+		mockClient.On("Do", mock.Anything).Return(newResponse(http.StatusOK, `{"success":true}`), nil).Once()
+
+		_, err := client.DeleteWorkspace(context.Background(), 1)
+
+		assert.NoError(t, err)
+		mockClient.AssertNumberOfCalls(t, "Do", 2)
+	})
+	t.Run("Given a server error on a non-idempotent request", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{config, mockClient}
+
+		mockClient.On("Do", mock.Anything).Return(newResponse(http.StatusServiceUnavailable, ""), nil).Once()
+
+		_, err := client.CreateWorkspace(context.Background())
+
+		assert.ErrorIs(t, err, model.APIErrSystemUnavailable)
+		mockClient.AssertNumberOfCalls(t, "Do", 1)
+	})
+	t.Run("Given a rate-limited non-idempotent request", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{config, mockClient}
+
+		mockClient.On("Do", mock.Anything).Return(newResponse(http.StatusTooManyRequests, ""), nil).Once()
+		mockClient.On("Do", mock.Anything).Return(newResponse(http.StatusOK, `{"id":1}`), nil).Once()
+
+		workspace, err := client.CreateWorkspace(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), workspace.ID)
+		mockClient.AssertNumberOfCalls(t, "Do", 2)
+	})
+	t.Run("Given retries are exhausted", func(t *testing.T) {
+		mockClient := new(MockHTTPClient)
+		client := &Client{config, mockClient}
+
+		mockClient.On("Do", mock.Anything).Return(newResponse(http.StatusBadGateway, ""), nil).Times(3)
+
+		_, err := client.GetWorkspaces(context.Background())
+
+		assert.ErrorIs(t, err, model.APIErrSystemUnavailable)
+		mockClient.AssertNumberOfCalls(t, "Do", 3)
+	})
+}
+
+// TestErrorStatus tests the errors returned for the HTTP error statuses and unexpected response bodies
+func TestErrorStatus(t *testing.T) {
+	config := &Config{
+		AdminAPIKey: "test-key",
+		BaseURL:     &url.URL{Scheme: "http", Host: "localhost:8080"},
+		UserAgent:   "test-agent",
+	}
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		expected    error
+	}{
+		{"Given a bad request", http.StatusBadRequest, "application/json", `{"message":"Invalid"}`, model.APIErrBadRequest},
+		{"Given an unauthorized request", http.StatusUnauthorized, "application/json", `{}`, model.APIErrUnauthorized},
+		{"Given a forbidden request", http.StatusForbidden, "application/json", `{}`, model.APIErrForbidden},
+		{"Given a missing workspace", http.StatusNotFound, "application/json", `{}`, model.APIErrNotFound},
+		{"Given a conflict", http.StatusConflict, "application/json", `{}`, model.APIErrConflict},
+		{"Given a rate-limited request", http.StatusTooManyRequests, "application/json", `{}`, model.APIErrRateLimited},
+		{"Given an HTML login page", http.StatusOK, "text/html; charset=utf-8", "<!DOCTYPE html><html></html>", model.APIErrUnexpectedContent},
+		{"Given an HTML body without content type", http.StatusOK, "", "\n  <html></html>", model.APIErrUnexpectedContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockHTTPClient)
+			client := &Client{config, mockClient}
+
+			resp := &http.Response{
+				StatusCode: tt.statusCode,
+				Status:     http.StatusText(tt.statusCode),
+				Header:     http.Header{"Content-Type": []string{tt.contentType}},
+				Body:       io.NopCloser(bytes.NewBufferString(tt.body)),
+			}
+
+			mockClient.On("Do", mock.Anything).Return(resp, nil).Once()
+
+			_, err := client.GetWorkspaces(context.Background())
+
+			assert.ErrorIs(t, err, tt.expected)
+
+			var apiErr *model.APIErrorResponse
+			if assert.ErrorAs(t, err, &apiErr) {
+				assert.Equal(t, tt.statusCode, apiErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected time.Duration
+		ok       bool
+	}{
+		{"Given no header", "", 0, false},
+		{"Given a number of seconds", "5", 5 * time.Second, true},
+		{"Given a date in the past", "Mon, 02 Jan 2006 15:04:05 GMT", 0, true},
+		{"Given an invalid value", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+
+			actual, ok := retryAfter(resp)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// signature holds the values required to authenticate a request against the Structurizr workspace API
+type signature struct {
+	Method      string
+	Path        string
+	ContentType string
+	Nonce       string
+	Body        []byte
+}
+
+// sign adds the HMAC-SHA256 based authorisation headers expected by the Structurizr workspace API
+// See https://docs.structurizr.com/onpremises/api#authentication for more info/background
+func (s *signature) sign(header http.Header, key string, secret string) {
+	contentMD5 := md5.Sum(s.Body)
+	contentMD5Hex := hex.EncodeToString(contentMD5[:])
+
+	content := new(strings.Builder)
+	content.WriteString(s.Method + "\n")
+	content.WriteString(s.Path + "\n")
+	content.WriteString(contentMD5Hex + "\n")
+	content.WriteString(s.ContentType + "\n")
+	content.WriteString(s.Nonce + "\n")
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(content.String()))
+	digest := hex.EncodeToString(mac.Sum(nil))
+
+	header.Set("X-Authorization", key+":"+base64.StdEncoding.EncodeToString([]byte(digest)))
+	header.Set("Nonce", s.Nonce)
+	header.Set("Content-MD5", base64.StdEncoding.EncodeToString([]byte(contentMD5Hex)))
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestSignature_Sign(t *testing.T) {
+	tests := []struct {
+		name                  string
+		signature             signature
+		expectedAuthorization string
+		expectedContentMD5    string
+	}{
+		{
+			name: "Given a request with a body",
+			signature: signature{
+				Method:      http.MethodPut,
+				Path:        "/api/workspace/1",
+				ContentType: "application/json; charset=UTF-8",
+				Nonce:       "1420070400000",
+				Body:        []byte(`{"id":1}`),
+			},
+			expectedAuthorization: "key:MmUxMGRkZWFkYjAyMjZmNWRkMjA1NjY3NmI3NzVmYzU2OWQyZGIzMjE2YTU2YWFjODMwODI5NDJmYWMwMjI0ZQ==",
+			expectedContentMD5:    "ZDJjZTI4YjlhN2ZkN2U0NDA3ZTJiMGZkNDk5YjdmZTQ=",
+		},
+		{
+			name: "Given a request without a body",
+			signature: signature{
+				Method: http.MethodGet,
+				Path:   "/api/workspace/1",
+				Nonce:  "1420070400000",
+			},
+			expectedAuthorization: "key:Y2UxNjU0NjlhN2NkYmIyMzk1ODM3NGM2MDA2ZmVmZGZiZTg4ZjEzZDMyY2EyZWRjMDliN2Y1ZWIwZDllYTNhZQ==",
+			expectedContentMD5:    "ZDQxZDhjZDk4ZjAwYjIwNGU5ODAwOTk4ZWNmODQyN2U=",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			tt.signature.sign(header, "key", "secret")
+
+			assert.Equal(t, tt.expectedAuthorization, header.Get("X-Authorization"))
+			assert.Equal(t, tt.expectedContentMD5, header.Get("Content-MD5"))
+			assert.Equal(t, tt.signature.Nonce, header.Get("Nonce"))
+		})
+	}
+}
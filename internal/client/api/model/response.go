@@ -3,6 +3,7 @@ package model
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 )
 
@@ -13,6 +14,16 @@ var (
 	APIErrSystemUnavailable = errors.New("system unavailable")
 	// APIErrUnauthorized represents an HTTP 401 error
 	APIErrUnauthorized = errors.New("unauthorized")
+	// APIErrForbidden represents an HTTP 403 error
+	APIErrForbidden = errors.New("forbidden")
+	// APIErrNotFound represents an HTTP 404 error
+	APIErrNotFound = errors.New("not found")
+	// APIErrConflict represents an HTTP 409 error, or a workspace locked by another user or agent
+	APIErrConflict = errors.New("conflict")
+	// APIErrRateLimited represents an HTTP 429 error
+	APIErrRateLimited = errors.New("rate limited")
+	// APIErrUnexpectedContent represents a response which is not JSON, such as an HTML login page of a reverse proxy
+	APIErrUnexpectedContent = errors.New("unexpected non-JSON response")
 )
 
 // APIResponse represents a response from structurizr
@@ -24,9 +35,10 @@ type APIResponse struct {
 
 // APIErrorResponse represents a body of the shape: {"success":false,"message":"error message"}
 type APIErrorResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	Err     error
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"-"`
+	Err        error
 }
 
 // Error implements the error interface
@@ -45,3 +57,28 @@ func (e *APIErrorResponse) Error() string {
 
 	return message.String()
 }
+
+// Unwrap returns the underlying error so errors.Is can be used against the APIErr* sentinels
+func (e *APIErrorResponse) Unwrap() error {
+	return e.Err
+}
+
+// ErrorForStatus returns the error matching an HTTP error status code
+func ErrorForStatus(statusCode int) error {
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		return APIErrUnauthorized
+	case statusCode == http.StatusForbidden:
+		return APIErrForbidden
+	case statusCode == http.StatusNotFound:
+		return APIErrNotFound
+	case statusCode == http.StatusConflict:
+		return APIErrConflict
+	case statusCode == http.StatusTooManyRequests:
+		return APIErrRateLimited
+	case statusCode >= 500:
+		return APIErrSystemUnavailable
+	default:
+		return APIErrBadRequest
+	}
+}
@@ -1,7 +1,9 @@
 package model
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 )
 
@@ -54,3 +56,39 @@ func TestAPIErrorResponse_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIErrorResponse_Unwrap(t *testing.T) {
+	err := error(&APIErrorResponse{Message: "Workspace not found", StatusCode: http.StatusNotFound, Err: APIErrNotFound})
+
+	if !errors.Is(err, APIErrNotFound) {
+		t.Errorf("expected %q to wrap %q", err, APIErrNotFound)
+	}
+	if errors.Is(err, APIErrBadRequest) {
+		t.Errorf("expected %q not to wrap %q", err, APIErrBadRequest)
+	}
+}
+
+func TestErrorForStatus(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		expected   error
+	}{
+		{http.StatusBadRequest, APIErrBadRequest},
+		{http.StatusUnauthorized, APIErrUnauthorized},
+		{http.StatusForbidden, APIErrForbidden},
+		{http.StatusNotFound, APIErrNotFound},
+		{http.StatusConflict, APIErrConflict},
+		{http.StatusTooManyRequests, APIErrRateLimited},
+		{http.StatusUnprocessableEntity, APIErrBadRequest},
+		{http.StatusInternalServerError, APIErrSystemUnavailable},
+		{http.StatusBadGateway, APIErrSystemUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			if actual := ErrorForStatus(tt.statusCode); actual != tt.expected {
+				t.Errorf("expected: %q, got: %q", tt.expected, actual)
+			}
+		})
+	}
+}
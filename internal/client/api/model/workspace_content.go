@@ -0,0 +1,26 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/crypto"
+)
+
+// WorkspaceContent represents the definition of a workspace as stored in the structurizr
+type WorkspaceContent struct {
+	ID                 int64               `json:"id"`
+	Name               string              `json:"name"`
+	Description        string              `json:"description"`
+	Revision           int64               `json:"revision"`
+	LastModifiedDate   string              `json:"lastModifiedDate"`
+	LastModifiedUser   string              `json:"lastModifiedUser"`
+	LastModifiedAgent  string              `json:"lastModifiedAgent"`
+	Ciphertext         string              `json:"ciphertext"`
+	EncryptionStrategy *crypto.AESStrategy `json:"encryptionStrategy"`
+	// Raw is the JSON document of the workspace as received from the remote server
+	Raw json.RawMessage `json:"-"`
+}
+
+// IsEncrypted reports whether the workspace has been encrypted on the client-side
+func (w *WorkspaceContent) IsEncrypted() bool {
+	return w.EncryptionStrategy != nil && w.Ciphertext != ""
+}
@@ -0,0 +1,22 @@
+package model
+
+// WorkspaceVersion represents a previous version of a workspace kept by the remote server
+type WorkspaceVersion struct {
+	VersionID string `json:"versionId"`
+	Timestamp string `json:"timestamp"`
+}
+
+// WorkspaceVersions is the response body listing the versions of a workspace, the latest version comes first
+type WorkspaceVersions struct {
+	Versions []*WorkspaceVersion `json:"versions"`
+}
+
+// FindByID returns a workspace version by its ID
+func (w *WorkspaceVersions) FindByID(versionID string) *WorkspaceVersion {
+	for _, version := range w.Versions {
+		if version.VersionID == versionID {
+			return version
+		}
+	}
+	return nil
+}
@@ -26,3 +26,14 @@ func (w *Workspaces) FindByID(id any) *Workspace {
 	}
 	return nil
 }
+
+// FindByName returns the workspaces with the given name, as names are not unique
+func (w *Workspaces) FindByName(name string) []*Workspace {
+	var workspaces []*Workspace
+	for _, workspace := range w.Workspaces {
+		if workspace.Name == name {
+			workspaces = append(workspaces, workspace)
+		}
+	}
+	return workspaces
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// archiveTimestamp is the layout of the timestamps in the names of the archives, as written by the Structurizr CLI
+const archiveTimestamp = "20060102150405"
+
+// ErrMergeUnsupported is returned when preserving the layout of a workspace pushed straight to the workspace API,
+// as merging the layout of the remote diagrams is only implemented by the Structurizr CLI
+var ErrMergeUnsupported = errors.New("preserving the layout of workspaces requires the cli push client")
+
+// PushOptions are the options of a push of a workspace
+type PushOptions struct {
+	// Merge merges the layout of the remote diagrams, such as the ones made in the Structurizr UI, into the pushed ones
+	Merge bool
+	// Archive writes the previous version of the remote workspace into ArchiveDir before it is replaced
+	Archive bool
+	// ArchiveDir is the directory the archives are written to
+	ArchiveDir string
+}
+
+// ArchiveName returns the name of the archive of a workspace made at the given time
+func ArchiveName(id int64, t time.Time) string {
+	return fmt.Sprintf("structurizr-%d-%s.json", id, t.Format(archiveTimestamp))
+}
+
+// PruneArchives removes the oldest archives of a workspace from dir, so only the given number of archives are kept
+func PruneArchives(dir string, id int64, keep int) error {
+	names, err := filepath.Glob(filepath.Join(dir, fmt.Sprintf("structurizr-%d-*.json", id)))
+	if err != nil || len(names) <= keep {
+		return err
+	}
+
+	// The timestamps of the names sort the archives from the oldest to the newest
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names[:len(names)-keep] {
+		if err = os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WriteArchive writes the content of a workspace into an archive of dir
+func WriteArchive(dir string, id int64, content []byte) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create the archive directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ArchiveName(id, time.Now())), content, 0o600); err != nil {
+		return fmt.Errorf("failed to archive workspace (id: %d): %w", id, err)
+	}
+
+	return nil
+}
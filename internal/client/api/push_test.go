@@ -0,0 +1,47 @@
+package api
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveName(t *testing.T) {
+	name := ArchiveName(12, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "structurizr-12-20240501100000.json", name)
+}
+
+func TestPruneArchives(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, ArchiveName(1, start.Add(time.Duration(i)*time.Hour))), nil, 0o600))
+	}
+	// The archives of the other workspaces and the other files are never removed
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ArchiveName(12, start)), nil, 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o600))
+
+	assert.NoError(t, PruneArchives(dir, 1, 2))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{
+		"structurizr-1-20240501120000.json",
+		"structurizr-1-20240501130000.json",
+		"structurizr-12-20240501100000.json",
+		"notes.txt",
+	}, names)
+
+	t.Run("Given fewer archives than kept", func(t *testing.T) {
+		assert.NoError(t, PruneArchives(dir, 12, 2))
+		assert.FileExists(t, filepath.Join(dir, "structurizr-12-20240501100000.json"))
+	})
+}
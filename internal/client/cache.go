@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+	"golang.org/x/sync/singleflight"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWorkspacesCacheTTL is the default duration the list of workspaces is kept before being requested again
+	DefaultWorkspacesCacheTTL = 30 * time.Second
+	// DefaultWorkspacesListTimeout is the default upper bound of a shared request of the list of workspaces,
+	// when the caller starting it has no deadline
+	DefaultWorkspacesListTimeout = 5 * time.Minute
+)
+
+// workspacesCache keeps the list of workspaces for a short time, and collapses concurrent requests of the list
+// into one, so refreshing many workspaces requests it only once. It must be invalidated by every mutation.
+type workspacesCache struct {
+	ttl time.Duration
+	// timeout bounds the shared request of the list when its caller has no deadline, DefaultWorkspacesListTimeout
+	// when not set
+	timeout time.Duration
+	group   singleflight.Group
+
+	mu         sync.Mutex
+	workspaces *model.Workspaces
+	expires    time.Time
+	// generation is incremented on every invalidation, a list requested before a mutation is never kept
+	generation uint64
+}
+
+// get returns the cached list of workspaces, otherwise the one returned by list, which is called at most once
+// at a time. The returned list is shared and must not be modified.
+func (c *workspacesCache) get(
+	ctx context.Context,
+	list func(ctx context.Context) (*model.Workspaces, error),
+) (*model.Workspaces, error) {
+	c.mu.Lock()
+	if c.workspaces != nil && time.Now().Before(c.expires) {
+		defer c.mu.Unlock()
+		return c.workspaces, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	// Only the requests of the same generation are collapsed, so none is answered with a list preceding a mutation.
+	// The shared request is not canceled along with the caller starting it, as the other callers are waiting for it,
+	// each caller stops waiting for it once its own context is done instead. It keeps a deadline of its own, so
+	// a hung server does not hold the later callers of the generation forever.
+	timeout := c.timeout
+	if timeout <= 0 {
+		timeout = DefaultWorkspacesListTimeout
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	ch := c.group.DoChan(strconv.FormatUint(generation, 10), func() (interface{}, error) {
+		listCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+
+		workspaces, err := list(listCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.generation == generation && c.ttl > 0 {
+			c.workspaces = workspaces
+			c.expires = time.Now().Add(c.ttl)
+		}
+
+		return workspaces, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
+		return res.Val.(*model.Workspaces), nil
+	}
+}
+
+// invalidate forgets the cached list of workspaces
+func (c *workspacesCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.workspaces = nil
+	c.generation++
+}
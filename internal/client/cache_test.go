@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingList is a list of workspaces counting how many times it is requested
+type countingList struct {
+	calls atomic.Int32
+	wait  chan struct{}
+	err   error
+}
+
+// list returns a list of workspaces, once wait is closed when set, unless the context is done first
+func (l *countingList) list(ctx context.Context) (*model.Workspaces, error) {
+	l.calls.Add(1)
+	if l.wait != nil {
+		select {
+		case <-l.wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	return &model.Workspaces{Workspaces: []*model.Workspace{{ID: 1}}}, nil
+}
+
+func TestWorkspacesCache(t *testing.T) {
+	t.Run("Given the list is cached", func(t *testing.T) {
+		c, l := &workspacesCache{ttl: time.Minute}, &countingList{}
+
+		first, err := c.get(context.Background(), l.list)
+		assert.NoError(t, err)
+		second, err := c.get(context.Background(), l.list)
+		assert.NoError(t, err)
+
+		assert.Same(t, first, second)
+		assert.Equal(t, int32(1), l.calls.Load())
+	})
+	t.Run("Given the list expired", func(t *testing.T) {
+		c, l := &workspacesCache{ttl: time.Millisecond}, &countingList{}
+
+		_, _ = c.get(context.Background(), l.list)
+		time.Sleep(5 * time.Millisecond)
+		_, _ = c.get(context.Background(), l.list)
+
+		assert.Equal(t, int32(2), l.calls.Load())
+	})
+	t.Run("Given the list is invalidated", func(t *testing.T) {
+		c, l := &workspacesCache{ttl: time.Minute}, &countingList{}
+
+		_, _ = c.get(context.Background(), l.list)
+		c.invalidate()
+		_, _ = c.get(context.Background(), l.list)
+
+		assert.Equal(t, int32(2), l.calls.Load())
+	})
+	t.Run("Given a failure", func(t *testing.T) {
+		c, l := &workspacesCache{ttl: time.Minute}, &countingList{err: errors.New("boom")}
+
+		_, err := c.get(context.Background(), l.list)
+		assert.Error(t, err)
+		_, err = c.get(context.Background(), l.list)
+		assert.Error(t, err)
+
+		assert.Equal(t, int32(2), l.calls.Load())
+	})
+	t.Run("Given concurrent requests", func(t *testing.T) {
+		c, l := &workspacesCache{ttl: time.Minute}, &countingList{wait: make(chan struct{})}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				workspaces, err := c.get(context.Background(), l.list)
+				assert.NoError(t, err)
+				assert.NotNil(t, workspaces.FindByID(int64(1)))
+			}()
+		}
+
+		// Letting the requests join the one in flight before it completes
+		time.Sleep(20 * time.Millisecond)
+		close(l.wait)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), l.calls.Load())
+	})
+	t.Run("Given the first caller is canceled", func(t *testing.T) {
+		c, l := &workspacesCache{ttl: time.Minute}, &countingList{wait: make(chan struct{})}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		canceled := make(chan error)
+		go func() {
+			_, err := c.get(ctx, l.list)
+			canceled <- err
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			workspaces, err := c.get(context.Background(), l.list)
+			assert.NoError(t, err)
+			assert.NotNil(t, workspaces.FindByID(int64(1)))
+		}()
+
+		// The canceled caller stops waiting, while the request keeps going for the other one
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		assert.ErrorIs(t, <-canceled, context.Canceled)
+		close(l.wait)
+		<-done
+
+		assert.Equal(t, int32(1), l.calls.Load())
+	})
+	t.Run("Given the list request hangs", func(t *testing.T) {
+		c, l := &workspacesCache{ttl: time.Minute, timeout: 10 * time.Millisecond}, &countingList{wait: make(chan struct{})}
+
+		_, err := c.get(context.Background(), l.list)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// The hung request does not keep the next callers waiting
+		_, err = c.get(context.Background(), l.list)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(2), l.calls.Load())
+	})
+	t.Run("Given the caller has a deadline", func(t *testing.T) {
+		c, l := &workspacesCache{ttl: time.Minute}, &countingList{wait: make(chan struct{})}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+
+		_, err := c.get(ctx, l.list)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// The shared request ends along with the deadline of its caller rather than after the default timeout,
+		// the next caller either joins it until then or requests the list again
+		_, err = c.get(context.Background(), func(ctx context.Context) (*model.Workspaces, error) {
+			return nil, errors.New("listed again")
+		})
+		assert.Error(t, err)
+		assert.Less(t, time.Since(started), time.Second)
+	})
+	t.Run("Given a mutation while the list is requested", func(t *testing.T) {
+		c, l := &workspacesCache{ttl: time.Minute}, &countingList{wait: make(chan struct{})}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = c.get(context.Background(), l.list)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		c.invalidate()
+		close(l.wait)
+		<-done
+
+		_, _ = c.get(context.Background(), l.list)
+
+		assert.Equal(t, int32(2), l.calls.Load())
+	})
+}
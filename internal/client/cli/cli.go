@@ -2,28 +2,57 @@ package cli
 
 import (
 	"context"
-	"embed"
+	"errors"
 	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/retry"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/transport"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"io/fs"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 )
 
-//go:embed tools/structurizr-cli/lib/*.jar tools/structurizr-cli/structurizr.sh tools/structurizr-cli/structurizr.bat
-var structurizrCLI embed.FS
+const (
+	basePath = "/api"
+	// DefaultTimeout is the default upper bound of a single run of the Structurizr CLI
+	DefaultTimeout = 10 * time.Minute
+)
+
+// transientFailure matches the Structurizr CLI outputs of failures which are likely to succeed when retried,
+// such as the remote server restarting or rate limiting the requests. Status codes are only matched after an HTTP
+// status context, e.g. "HTTP 503" or "status code: 429", not to retry on the line numbers of DSL parsing errors.
+var transientFailure = regexp.MustCompile(
+	`(?i)(ConnectException|SocketTimeoutException|NoHttpResponseException|Connection (refused|reset)|` +
+		`(status code|HTTP(/[0-9.]+)?( error)?)[\s:=]*(429|502|503|504)\b|` +
+		`Too Many Requests|Bad Gateway|Service Unavailable|Gateway Time-?out)`,
+)
 
-const basePath = "/api"
+// findingLine matches the errors and warnings printed by the inspection of a workspace,
+// e.g. "[WARNING] model.softwaresystem.description | Add a description to the software system named "Billing"."
+var findingLine = regexp.MustCompile(`^\s*(?:-\s*)?\[(ERROR|WARNING)]\s*(.+?)\s*$`)
+
+// Finding is an error or a warning found by the inspection of a workspace
+type Finding struct {
+	// Severity is either "error" or "warning"
+	Severity string
+	Message  string
+}
 
 // Config is the primary means to modify the Client
 type Config struct {
 	BaseURL    *url.URL
 	WorkingDir string
-	goos       string
+	Retry      retry.Policy
+	Transport  transport.Config
+	// Timeout bounds every run of the Structurizr CLI, retries excluded. Zero means no timeout.
+	Timeout time.Duration
+	goos    string
 }
 
 // Client is the main Structurizr CLI
@@ -41,7 +70,9 @@ func NewClient(config *Config, executor CmdExec) *Client {
 	return &Client{config, executor}
 }
 
-// PushWorkspace push a new version of a workspace from an existing file
+// PushWorkspace push a new version of a workspace from an existing file.
+// The Structurizr CLI archives the previous version of the workspace into its working directory, so it runs
+// from the archive directory when archiving is requested.
 func (c *Client) PushWorkspace(
 	ctx context.Context,
 	id int64,
@@ -49,71 +80,81 @@ func (c *Client) PushWorkspace(
 	secret string,
 	passphrase string,
 	source string,
+	options api.PushOptions,
 ) error {
-	return c.execute(
+	var dir string
+	if options.Archive {
+		if err := os.MkdirAll(options.ArchiveDir, 0o700); err != nil {
+			return fmt.Errorf("failed to create the archive directory: %w", err)
+		}
+
+		// The source is resolved before changing the working directory of the Structurizr CLI
+		abs, err := filepath.Abs(source)
+		if err != nil {
+			return err
+		}
+		source, dir = abs, options.ArchiveDir
+	}
+
+	_, err := c.execute(
 		ctx,
+		dir,
 		"push",
 		"-id", strconv.FormatInt(id, 10),
 		"-key", key,
 		"-secret", secret,
 		"-passphrase", passphrase,
 		"-workspace", source,
-		"-url", c.config.BaseURL.JoinPath(basePath).String(),
-		"-merge", "false",
-		"-archive", "true",
+		"-url", c.apiURL(),
+		"-merge", strconv.FormatBool(options.Merge),
+		"-archive", strconv.FormatBool(options.Archive),
 	)
+	return err
 }
 
-// WorkingDir extracts the embedded Structurizr CLI files to a working directory for easier utilization.
-func WorkingDir(ctx context.Context) (string, error) {
-	// Get the path to the directory where the executable is running
-	dir, err := os.Executable()
-	if err != nil {
-		return "", fmt.Errorf("error getting executable directory: %v", err)
-	}
-	dir = filepath.Join(filepath.Dir(dir), "structurizr-cli")
+// ValidateWorkspace validates a workspace source without pushing it, the error holds the reason it is invalid
+func (c *Client) ValidateWorkspace(ctx context.Context, source string) error {
+	_, err := c.execute(ctx, "", "validate", "-workspace", source)
+	return err
+}
 
-	tflog.Debug(ctx, fmt.Sprintf("Structurizr CLI working directory: %s", dir))
+// InspectWorkspace inspects a workspace source and returns the errors and warnings found, such as elements without
+// description. The Structurizr CLI fails when the inspection finds errors, they are returned nonetheless.
+func (c *Client) InspectWorkspace(ctx context.Context, source string) ([]Finding, error) {
+	out, err := c.execute(ctx, "", "inspect", "-workspace", source)
 
-	if _, err := os.Stat(dir); err == nil {
-		return dir, nil
+	findings := parseFindings(out)
+	if err != nil && len(findings) == 0 {
+		return nil, err
 	}
 
-	err = fs.WalkDir(structurizrCLI, ".", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Ignore the root directory
-		if path == "." {
-			return nil
-		}
-
-		destPath := filepath.Join(
-			dir,
-			strings.TrimPrefix(strings.TrimPrefix(path, "tools"), "/structurizr-cli"),
-		)
-		if d.IsDir() {
-			return os.MkdirAll(destPath, os.ModePerm)
-		}
+	return findings, nil
+}
 
-		data, err := structurizrCLI.ReadFile(path)
-		if err != nil {
-			return err
+// parseFindings parses the errors and warnings printed by the inspection of a workspace, one per line
+func parseFindings(out []byte) []Finding {
+	var findings []Finding
+	for _, line := range strings.Split(string(out), "\n") {
+		if m := findingLine.FindStringSubmatch(line); m != nil {
+			findings = append(findings, Finding{Severity: strings.ToLower(m[1]), Message: m[2]})
 		}
+	}
 
-		return os.WriteFile(destPath, data, os.ModePerm)
-	})
+	return findings
+}
 
-	if err != nil {
-		return "", fmt.Errorf("failed to extract files: %w", err)
+// apiURL returns the URL of the API of the remote server, the cloud service serves it without the /api prefix
+func (c *Client) apiURL() string {
+	if api.IsCloudService(c.config.BaseURL) {
+		return c.config.BaseURL.String()
 	}
 
-	return dir, nil
+	return c.config.BaseURL.JoinPath(basePath).String()
 }
 
 // execute executes the Structurizr CLI commands with provided options on operating systems that support batch or shell scripts.
-func (c *Client) execute(ctx context.Context, options ...string) error {
+// The commands run from dir, or from the current directory when it is empty.
+func (c *Client) execute(ctx context.Context, dir string, options ...string) ([]byte, error) {
 	var name string
 	if c.config.goos == "windows" {
 		name = filepath.Join(c.config.WorkingDir, "structurizr.bat")
@@ -121,13 +162,80 @@ func (c *Client) execute(ctx context.Context, options ...string) error {
 		name = filepath.Join(c.config.WorkingDir, "structurizr.sh")
 	}
 
-	// Run the command and capture the output
-	out, err := c.cmdExec.CombinedOutput(ctx, name, options...)
+	env, cleanup, err := c.javaEnv()
 	if err != nil {
-		return fmt.Errorf("error running Structurizr CLI: %v\nOutput: %s", err, string(out))
+		return nil, fmt.Errorf("error configuring the JVM of the Structurizr CLI: %w", err)
 	}
+	defer cleanup()
+
+	for attempt := 1; ; attempt++ {
+		// Run the command and capture the output
+		out, err := c.run(ctx, Command{Name: name, Args: options, Env: env, Dir: dir})
+		if err == nil {
+			tflog.Debug(ctx, fmt.Sprintf("Structurizr CLI output: %s\n", string(out)))
+			return out, nil
+		}
 
-	tflog.Debug(ctx, fmt.Sprintf("Structurizr CLI output: %s\n", string(out)))
+		if attempt > c.config.Retry.MaxRetries || ctx.Err() != nil || !transientFailure.Match(out) {
+			return out, fmt.Errorf("error running Structurizr CLI %s: %w\nOutput: %s", Version(), err, string(out))
+		}
+
+		wait := c.config.Retry.Backoff(attempt)
+		tflog.Warn(ctx, fmt.Sprintf(
+			"retrying Structurizr CLI in %s (attempt %d of %d) after: %v\nOutput: %s",
+			wait,
+			attempt,
+			c.config.Retry.MaxRetries,
+			err,
+			string(out),
+		))
+
+		if err = retry.Wait(ctx, wait); err != nil {
+			return out, err
+		}
+	}
+}
+
+// run runs a single Structurizr CLI command, terminating it once the configured timeout is exceeded
+func (c *Client) run(ctx context.Context, cmd Command) ([]byte, error) {
+	if c.config.Timeout <= 0 {
+		return c.cmdExec.CombinedOutput(ctx, cmd)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	out, err := c.cmdExec.CombinedOutput(runCtx, cmd)
+	if err != nil && ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return out, fmt.Errorf("timed out after %s: %w", c.config.Timeout, err)
+	}
+
+	return out, err
+}
+
+// javaEnv returns the environment passing the TLS and proxy settings to the JVM through JAVA_TOOL_OPTIONS.
+// The trust and key stores are written to a private temporary directory removed by the returned function.
+func (c *Client) javaEnv() ([]string, func(), error) {
+	if !c.config.Transport.HasJavaOptions() {
+		return nil, func() {}, nil
+	}
+
+	dir, err := os.MkdirTemp("", "structurizr-cli-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	options, err := c.config.Transport.JavaOptions(dir)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	// Preserving the options already set by the user, such as the heap size
+	if v := os.Getenv("JAVA_TOOL_OPTIONS"); v != "" {
+		options = append([]string{v}, options...)
+	}
 
-	return nil
+	return []string{"JAVA_TOOL_OPTIONS=" + strings.Join(options, " ")}, cleanup, nil
 }
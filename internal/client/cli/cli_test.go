@@ -3,12 +3,17 @@ package cli
 import (
 	"context"
 	"errors"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/retry"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/transport"
 	"github.com/stretchr/testify/assert"
 	"net/url"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -24,6 +29,8 @@ func TestNewClient(t *testing.T) {
 }
 
 func TestPushWorkspace(t *testing.T) {
+	archiveDir := filepath.Join(t.TempDir(), "archives")
+	source, _ := filepath.Abs("response_workspace.tmpl")
 	cmdExecMock := &mockCmdExec{
 		output:       []byte("mocked output"),
 		err:          nil,
@@ -34,19 +41,23 @@ func TestPushWorkspace(t *testing.T) {
 			"-key", "key",
 			"-secret", "secret",
 			"-passphrase", "passphrase",
-			"-workspace", "response_workspace.tmpl",
+			"-workspace", source,
 			"-url", "http://localhost/api",
 			"-merge", "false",
 			"-archive", "true",
 		},
 	}
 	baseURL, _ := url.Parse("http://localhost")
-	client := &Client{config: &Config{baseURL, "/tmp", runtime.GOOS}, cmdExec: cmdExecMock}
+	client := &Client{config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS}, cmdExec: cmdExecMock}
 
-	err := client.PushWorkspace(context.TODO(), 12345, "key", "secret", "passphrase", "response_workspace.tmpl")
+	options := api.PushOptions{Archive: true, ArchiveDir: archiveDir}
+	err := client.PushWorkspace(context.TODO(), 12345, "key", "secret", "passphrase", "response_workspace.tmpl", options)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
+	// The Structurizr CLI archives into its working directory
+	assert.Equal(t, archiveDir, cmdExecMock.capturedDir)
+	assert.DirExists(t, archiveDir)
 	if cmdExecMock.capturedName != cmdExecMock.expectedName {
 		t.Fatalf("expected command name %q, got %q", cmdExecMock.expectedName, cmdExecMock.capturedName)
 	}
@@ -55,6 +66,101 @@ func TestPushWorkspace(t *testing.T) {
 	}
 }
 
+func TestPushWorkspace_PreserveLayout(t *testing.T) {
+	cmdExecMock := &mockCmdExec{output: []byte("mocked output")}
+	baseURL, _ := url.Parse("http://localhost")
+	client := &Client{config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS}, cmdExec: cmdExecMock}
+
+	err := client.PushWorkspace(context.TODO(), 12345, "key", "secret", "", "workspace.dsl", api.PushOptions{Merge: true})
+
+	assert.NoError(t, err)
+	assert.Empty(t, cmdExecMock.capturedDir)
+	assert.Contains(
+		t,
+		strings.Join(cmdExecMock.capturedArgs, " "),
+		"-workspace workspace.dsl -url http://localhost/api -merge true -archive false",
+	)
+}
+
+func TestPushWorkspace_CloudService(t *testing.T) {
+	cmdExecMock := &mockCmdExec{output: []byte("mocked output")}
+	baseURL, _ := url.Parse("https://api.structurizr.com")
+	client := &Client{config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS}, cmdExec: cmdExecMock}
+
+	err := client.PushWorkspace(context.TODO(), 12345, "key", "secret", "", "workspace.dsl", api.PushOptions{})
+
+	assert.NoError(t, err)
+	assert.Contains(t, strings.Join(cmdExecMock.capturedArgs, " "), "-url https://api.structurizr.com -merge")
+}
+
+func TestValidateWorkspace(t *testing.T) {
+	baseURL, _ := url.Parse("http://localhost")
+
+	t.Run("Given a valid workspace", func(t *testing.T) {
+		cmdExecMock := &mockCmdExec{output: []byte("workspace.dsl is valid")}
+		client := &Client{config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS}, cmdExec: cmdExecMock}
+
+		err := client.ValidateWorkspace(context.TODO(), "workspace.dsl")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"validate", "-workspace", "workspace.dsl"}, cmdExecMock.capturedArgs)
+	})
+	t.Run("Given an invalid workspace", func(t *testing.T) {
+		cmdExecMock := &mockCmdExec{
+			output: []byte("Unexpected tokens (expected: model, views) at line 2 of workspace.dsl: !invalid"),
+			err:    errors.New("exit status 1"),
+		}
+		client := &Client{config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS}, cmdExec: cmdExecMock}
+
+		err := client.ValidateWorkspace(context.TODO(), "workspace.dsl")
+
+		assert.ErrorContains(t, err, "at line 2 of workspace.dsl: !invalid")
+	})
+}
+
+func TestInspectWorkspace(t *testing.T) {
+	baseURL, _ := url.Parse("http://localhost")
+	output := []byte(` - inspecting workspace.dsl
+ - [ERROR] model.softwaresystem.description | Add a description to the software system named "Billing".
+[WARNING] views.systemlandscapeview.description | Add a description to the system landscape view.
+[INFO] model.person.description | Add a description to the person named "User".
+ - found 3 violations
+`)
+	expected := []Finding{
+		{"error", `model.softwaresystem.description | Add a description to the software system named "Billing".`},
+		{"warning", "views.systemlandscapeview.description | Add a description to the system landscape view."},
+	}
+
+	t.Run("Given findings", func(t *testing.T) {
+		cmdExecMock := &mockCmdExec{output: output}
+		client := &Client{config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS}, cmdExec: cmdExecMock}
+
+		findings, err := client.InspectWorkspace(context.TODO(), "workspace.dsl")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, findings)
+		assert.Equal(t, []string{"inspect", "-workspace", "workspace.dsl"}, cmdExecMock.capturedArgs)
+	})
+	t.Run("Given a failure because of the findings", func(t *testing.T) {
+		cmdExecMock := &mockCmdExec{output: output, err: errors.New("exit status 1")}
+		client := &Client{config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS}, cmdExec: cmdExecMock}
+
+		findings, err := client.InspectWorkspace(context.TODO(), "workspace.dsl")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, findings)
+	})
+	t.Run("Given a failure", func(t *testing.T) {
+		cmdExecMock := &mockCmdExec{output: []byte("Unknown command: inspect"), err: errors.New("exit status 1")}
+		client := &Client{config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS}, cmdExec: cmdExecMock}
+
+		findings, err := client.InspectWorkspace(context.TODO(), "workspace.dsl")
+
+		assert.ErrorContains(t, err, "Unknown command: inspect")
+		assert.Nil(t, findings)
+	})
+}
+
 func TestExecute(t *testing.T) {
 	baseURL, _ := url.Parse("http://localhost")
 	type fields struct {
@@ -74,7 +180,7 @@ func TestExecute(t *testing.T) {
 		{
 			"Given a simple command",
 			fields{
-				config: &Config{baseURL, "/tmp", runtime.GOOS},
+				config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS},
 				cmdExec: &mockCmdExec{
 					output:       []byte("mocked output"),
 					err:          nil,
@@ -91,7 +197,7 @@ func TestExecute(t *testing.T) {
 		{
 			"Given a command executed on windows",
 			fields{
-				config: &Config{baseURL, "/tmp", "windows"},
+				config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: "windows"},
 				cmdExec: &mockCmdExec{
 					output:       []byte("mocked output"),
 					err:          nil,
@@ -108,7 +214,7 @@ func TestExecute(t *testing.T) {
 		{
 			"Given a failure during command execution",
 			fields{
-				config: &Config{baseURL, "/tmp", runtime.GOOS},
+				config: &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS},
 				cmdExec: &mockCmdExec{
 					output:       []byte("mocked output"),
 					err:          errors.New("oops, command failed"),
@@ -128,7 +234,7 @@ func TestExecute(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Client{config: tt.fields.config, cmdExec: tt.fields.cmdExec}
 
-			if err := c.execute(tt.args.ctx, tt.args.options...); (err != nil) != tt.wantErr {
+			if _, err := c.execute(tt.args.ctx, "", tt.args.options...); (err != nil) != tt.wantErr {
 				t.Errorf("execute() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if tt.fields.cmdExec.capturedName != tt.fields.cmdExec.expectedName {
@@ -140,3 +246,161 @@ func TestExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestExecute_JavaOptions(t *testing.T) {
+	baseURL, _ := url.Parse("http://localhost")
+	proxyURL, _ := url.Parse("http://proxy.internal:3128")
+	t.Setenv("JAVA_TOOL_OPTIONS", "-Xmx512m")
+
+	t.Run("Given a proxy", func(t *testing.T) {
+		cmdExec := &mockCmdExec{output: []byte("ok")}
+		c := &Client{
+			config: &Config{
+				BaseURL:    baseURL,
+				WorkingDir: "/tmp",
+				Transport:  transport.Config{ProxyURL: proxyURL},
+				goos:       runtime.GOOS,
+			},
+			cmdExec: cmdExec,
+		}
+
+		_, err := c.execute(context.TODO(), "", "push")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"JAVA_TOOL_OPTIONS=-Xmx512m -Dhttp.proxyHost=proxy.internal -Dhttp.proxyPort=3128 " +
+				"-Dhttps.proxyHost=proxy.internal -Dhttps.proxyPort=3128",
+		}, cmdExec.capturedEnv)
+	})
+	t.Run("Given no transport configuration", func(t *testing.T) {
+		cmdExec := &mockCmdExec{output: []byte("ok")}
+		c := &Client{
+			config:  &Config{BaseURL: baseURL, WorkingDir: "/tmp", goos: runtime.GOOS},
+			cmdExec: cmdExec,
+		}
+
+		_, err := c.execute(context.TODO(), "", "push")
+
+		assert.NoError(t, err)
+		assert.Empty(t, cmdExec.capturedEnv)
+	})
+}
+
+// sequenceCmdExec is a CmdExec returning a predefined sequence of results
+type sequenceCmdExec struct {
+	outputs [][]byte
+	errs    []error
+	calls   int
+}
+
+// CombinedOutput returns the next predefined result
+func (m *sequenceCmdExec) CombinedOutput(_ context.Context, _ Command) ([]byte, error) {
+	i := m.calls
+	m.calls++
+	return m.outputs[i], m.errs[i]
+}
+
+func TestExecute_Retry(t *testing.T) {
+	baseURL, _ := url.Parse("http://localhost")
+	policy := retry.Policy{MaxRetries: 2, MinWait: time.Millisecond, MaxWait: time.Millisecond}
+	failure := errors.New("exit status 1")
+
+	tests := []struct {
+		name          string
+		cmdExec       *sequenceCmdExec
+		wantErr       bool
+		expectedCalls int
+	}{
+		{
+			"Given a transient failure",
+			&sequenceCmdExec{
+				outputs: [][]byte{[]byte("java.net.ConnectException: Connection refused"), []byte("ok")},
+				errs:    []error{failure, nil},
+			},
+			false,
+			2,
+		},
+		{
+			"Given a permanent failure",
+			&sequenceCmdExec{
+				outputs: [][]byte{[]byte("The workspace could not be parsed")},
+				errs:    []error{failure},
+			},
+			true,
+			1,
+		},
+		{
+			"Given retries are exhausted",
+			&sequenceCmdExec{
+				outputs: [][]byte{[]byte("HTTP 503"), []byte("HTTP 503"), []byte("HTTP 503")},
+				errs:    []error{failure, failure, failure},
+			},
+			true,
+			3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				config:  &Config{BaseURL: baseURL, WorkingDir: "/tmp", Retry: policy, goos: runtime.GOOS},
+				cmdExec: tt.cmdExec,
+			}
+
+			if _, err := c.execute(context.TODO(), "", "push"); (err != nil) != tt.wantErr {
+				t.Errorf("execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.expectedCalls, tt.cmdExec.calls)
+		})
+	}
+}
+
+func TestTransientFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected bool
+	}{
+		{"Given a connection failure", "java.net.ConnectException: Connection refused", true},
+		{"Given an HTTP status", "HTTP 503", true},
+		{"Given an HTTP status line", "HTTP/1.1 502 Bad Gateway", true},
+		{"Given a status code", "The API responded with status code: 429", true},
+		{"Given a status reason", "Service Unavailable", true},
+		{"Given a line number", "workspace.dsl: Unexpected tokens at line 503", false},
+		{"Given a workspace identifier", "Workspace 429 could not be found", false},
+		{"Given another HTTP status", "HTTP 500", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, transientFailure.MatchString(tt.output))
+		})
+	}
+}
+
+// blockingCmdExec is a CmdExec hanging until the context is done
+type blockingCmdExec struct{}
+
+// CombinedOutput waits for the context to be done
+func (m *blockingCmdExec) CombinedOutput(ctx context.Context, _ Command) ([]byte, error) {
+	<-ctx.Done()
+	return nil, errors.New("signal: killed")
+}
+
+func TestExecute_Timeout(t *testing.T) {
+	baseURL, _ := url.Parse("http://localhost")
+	c := &Client{
+		config: &Config{
+			BaseURL:    baseURL,
+			WorkingDir: "/tmp",
+			Retry:      retry.Policy{MaxRetries: 2, MinWait: time.Millisecond, MaxWait: time.Millisecond},
+			Timeout:    10 * time.Millisecond,
+			goos:       runtime.GOOS,
+		},
+		cmdExec: &blockingCmdExec{},
+	}
+
+	_, err := c.execute(context.TODO(), "", "push")
+
+	assert.ErrorContains(t, err, "timed out after 10ms")
+}
@@ -2,12 +2,29 @@ package cli
 
 import (
 	"context"
+	"os"
 	"os/exec"
+	"time"
 )
 
+// waitDelay bounds the wait for the output of a cancelled command, in case a descendant survived and holds its pipes
+const waitDelay = 5 * time.Second
+
+// Command describes a command to execute
+type Command struct {
+	// Name is the name or the path of the executable
+	Name string
+	// Args are the arguments passed to the executable
+	Args []string
+	// Env are environment variables in the form "key=value" added to the environment of the current process
+	Env []string
+	// Dir is the working directory of the command, the current directory when empty
+	Dir string
+}
+
 // CmdExec is an interface for executing commands
 type CmdExec interface {
-	CombinedOutput(ctx context.Context, name string, arg ...string) ([]byte, error)
+	CombinedOutput(ctx context.Context, cmd Command) ([]byte, error)
 }
 
 // cmdExecutor is an implementation of cmdExecutor that uses exec.Command
@@ -16,8 +33,18 @@ type cmdExecutor struct{}
 // DefaultCmdExec is the default cmdExecutor.
 var DefaultCmdExec = &cmdExecutor{}
 
-// CombinedOutput runs a command and returns its combined standard output and standard error
-func (e *cmdExecutor) CombinedOutput(ctx context.Context, name string, arg ...string) ([]byte, error) {
-	cmd := exec.CommandContext(ctx, name, arg...)
-	return cmd.CombinedOutput()
+// CombinedOutput runs a command and returns its combined standard output and standard error.
+// When the context is done, the command and all its descendants (e.g. the JVM started by the
+// Structurizr CLI scripts) are terminated.
+func (e *cmdExecutor) CombinedOutput(ctx context.Context, cmd Command) ([]byte, error) {
+	c := exec.CommandContext(ctx, cmd.Name, cmd.Args...)
+	if len(cmd.Env) > 0 {
+		c.Env = append(os.Environ(), cmd.Env...)
+	}
+	c.Dir = cmd.Dir
+	setProcessGroup(c)
+	c.Cancel = func() error { return killProcessGroup(c) }
+	c.WaitDelay = waitDelay
+
+	return c.CombinedOutput()
 }
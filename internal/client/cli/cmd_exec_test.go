@@ -14,12 +14,16 @@ type mockCmdExec struct {
 	err          error
 	capturedName string
 	capturedArgs []string
+	capturedEnv  []string
+	capturedDir  string
 }
 
 // CombinedOutput is capturing and storing the input so later it can be asserted
-func (m *mockCmdExec) CombinedOutput(_ context.Context, name string, arg ...string) ([]byte, error) {
-	m.capturedName = name
-	m.capturedArgs = arg
+func (m *mockCmdExec) CombinedOutput(_ context.Context, cmd Command) ([]byte, error) {
+	m.capturedName = cmd.Name
+	m.capturedArgs = cmd.Args
+	m.capturedEnv = cmd.Env
+	m.capturedDir = cmd.Dir
 	return m.output, m.err
 }
 
@@ -28,7 +32,7 @@ func TestCmdExec_CombinedOutput_Simple(t *testing.T) {
 	executor := DefaultCmdExec
 
 	// Test a simple command
-	output, err := executor.CombinedOutput(ctx, "echo", "hello")
+	output, err := executor.CombinedOutput(ctx, Command{Name: "echo", Args: []string{"hello"}})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -39,10 +43,20 @@ func TestCmdExec_CombinedOutput_Simple(t *testing.T) {
 	}
 
 	// Test a command that returns an error
-	_, err = executor.CombinedOutput(ctx, "false")
+	_, err = executor.CombinedOutput(ctx, Command{Name: "false"})
 	if err == nil {
 		t.Fatalf("expected an error, got none")
 	}
+
+	// Test a command with additional environment variables
+	output, err = executor.CombinedOutput(ctx, Command{Name: "sh", Args: []string{"-c", "echo $STRUCTURIZR_TEST"}, Env: []string{"STRUCTURIZR_TEST=hello"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if string(output) != expectedOutput {
+		t.Fatalf("expected output %q, got %q", expectedOutput, string(output))
+	}
 }
 
 func TestCmdExec_CombinedOutput(t *testing.T) {
@@ -54,7 +68,7 @@ func TestCmdExec_CombinedOutput(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	output, err := mockExecutor.CombinedOutput(ctx, mockExecutor.expectedName, []string{"arg1", "arg2"}...)
+	output, err := mockExecutor.CombinedOutput(ctx, Command{Name: mockExecutor.expectedName, Args: []string{"arg1", "arg2"}})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
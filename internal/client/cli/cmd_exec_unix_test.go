@@ -0,0 +1,56 @@
+//go:build !windows
+
+package cli
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCmdExec_CombinedOutput_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	// The shell starts a child process outliving it, as the Structurizr CLI scripts do with the JVM
+	start := time.Now()
+	output, err := DefaultCmdExec.CombinedOutput(ctx, Command{Name: "sh", Args: []string{"-c", "sleep 30 & echo $!; wait"}})
+	if err == nil {
+		t.Fatalf("expected an error, got none")
+	}
+	if elapsed := time.Since(start); elapsed >= waitDelay {
+		t.Fatalf("expected the command to be terminated on cancellation, took %s", elapsed)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		t.Fatalf("expected the pid of the child process, got %q", string(output))
+	}
+
+	// The child process must be gone, or at most a zombie waiting to be reaped, once the signal is delivered
+	var stat []byte
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		stat, err = os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+		if err != nil || strings.Contains(string(stat), ") Z ") {
+			return
+		}
+	}
+
+	t.Fatalf("expected the child process %d to be terminated, got %q", pid, string(stat))
+}
+
+func TestCmdExec_CombinedOutput_Dir(t *testing.T) {
+	dir := t.TempDir()
+
+	output, err := DefaultCmdExec.CombinedOutput(context.Background(), Command{Name: "pwd", Dir: dir})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got := strings.TrimSpace(string(output)); got != dir {
+		t.Fatalf("expected the command to run from %q, got %q", dir, got)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package cli
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup is a no-op on Windows where the process tree is terminated with taskkill
+func setProcessGroup(_ *exec.Cmd) {}
+
+// killProcessGroup kills the process tree of a command, falling back to the command itself
+func killProcessGroup(c *exec.Cmd) error {
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(c.Process.Pid)).Run(); err != nil {
+		return c.Process.Kill()
+	}
+
+	return nil
+}
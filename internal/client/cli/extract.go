@@ -0,0 +1,284 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed tools/structurizr-cli/lib/*.jar tools/structurizr-cli/structurizr.sh tools/structurizr-cli/structurizr.bat
+//go:embed tools/structurizr-cli/version.txt
+var structurizrCLI embed.FS
+
+const (
+	// dirPrefix is the prefix of the directories of the extracted versions of the Structurizr CLI
+	dirPrefix = "structurizr-cli-"
+	// manifestName is the file written once a version of the Structurizr CLI is completely extracted and verified.
+	// It holds the digest of the extracted files and its modification time records when it was last used.
+	manifestName = ".manifest"
+	// staleAfter is the duration after which an unused version of the Structurizr CLI is removed
+	staleAfter = 24 * time.Hour
+)
+
+// embedded is the Structurizr CLI embedded in the provider, it is hashed once on first use
+var embedded = sync.OnceValues(func() (*bundle, error) {
+	fsys, err := fs.Sub(structurizrCLI, "tools/structurizr-cli")
+	if err != nil {
+		return nil, err
+	}
+
+	return newBundle(fsys)
+})
+
+// Version returns the version of the embedded Structurizr CLI
+func Version() string {
+	b, err := embedded()
+	if err != nil {
+		return "unknown"
+	}
+
+	return b.version
+}
+
+// WorkingDir extracts the embedded Structurizr CLI files to a working directory for easier utilization.
+// Every version is extracted into its own directory of baseDir, which defaults to the user cache directory,
+// so upgrading the provider never runs a previous version. The extraction is atomic and verified against
+// the checksums of the embedded files, and the versions which are no longer used are removed.
+func WorkingDir(ctx context.Context, baseDir string) (string, error) {
+	b, err := embedded()
+	if err != nil {
+		return "", fmt.Errorf("error reading the embedded Structurizr CLI: %w", err)
+	}
+
+	if baseDir == "" {
+		if baseDir, err = DefaultBaseDir(); err != nil {
+			return "", err
+		}
+	}
+
+	dir, err := b.extract(ctx, baseDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract Structurizr CLI %s: %w", b.version, err)
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Structurizr CLI %s working directory: %s", b.version, dir))
+
+	removeStale(ctx, baseDir, filepath.Base(dir), time.Now())
+
+	return dir, nil
+}
+
+// DefaultBaseDir returns the directory of the provider in the user cache directory
+func DefaultBaseDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting user cache directory: %w", err)
+	}
+
+	return filepath.Join(dir, "terraform-provider-structurizr"), nil
+}
+
+// bundle is a version of the Structurizr CLI along with the checksums of its files
+type bundle struct {
+	fsys    fs.FS
+	version string
+	sums    map[string][sha256.Size]byte
+	digest  string
+}
+
+// newBundle hashes the files of a Structurizr CLI, the digest covers both their paths and their content
+func newBundle(fsys fs.FS) (*bundle, error) {
+	version, err := fs.ReadFile(fsys, "version.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bundle{fsys: fsys, version: strings.TrimSpace(string(version)), sums: map[string][sha256.Size]byte{}}
+	h := sha256.New()
+
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(data)
+		b.sums[name] = sum
+		_, _ = fmt.Fprintf(h, "%s\x00%x\n", name, sum)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b.digest = hex.EncodeToString(h.Sum(nil))
+
+	return b, nil
+}
+
+// dirName returns the name of the directory of this version of the Structurizr CLI
+func (b *bundle) dirName() string {
+	return dirPrefix + b.version + "-" + b.digest[:12]
+}
+
+// extract extracts the Structurizr CLI into its directory of baseDir, unless it has already been extracted.
+// The files are written and verified in a temporary directory which is then renamed, so concurrent extractions
+// by parallel Terraform processes never see a partially extracted directory.
+func (b *bundle) extract(ctx context.Context, baseDir string) (string, error) {
+	dir := filepath.Join(baseDir, b.dirName())
+	if b.isExtracted(dir) {
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.MkdirTemp(baseDir, "."+b.dirName()+"-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	tflog.Debug(ctx, fmt.Sprintf("Extracting Structurizr CLI %s to %s", b.version, dir))
+
+	if err = b.write(tmp); err != nil {
+		return "", err
+	}
+
+	if err = b.verify(tmp); err != nil {
+		return "", err
+	}
+
+	if err = os.WriteFile(filepath.Join(tmp, manifestName), []byte(b.digest), 0o644); err != nil {
+		return "", err
+	}
+
+	if err = os.Rename(tmp, dir); err != nil {
+		// Another process may have completed the same extraction in the meantime
+		if b.isExtracted(dir) {
+			return dir, nil
+		}
+
+		// A previous extraction is incomplete or corrupted, it is replaced
+		if rmErr := os.RemoveAll(dir); rmErr != nil {
+			return "", fmt.Errorf("failed to replace the incomplete extraction %s: %w", dir, errors.Join(err, rmErr))
+		}
+
+		if err = os.Rename(tmp, dir); err != nil && !b.isExtracted(dir) {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// isExtracted reports whether this version has been completely extracted into dir, and records its use
+func (b *bundle) isExtracted(dir string) bool {
+	manifest := filepath.Join(dir, manifestName)
+
+	data, err := os.ReadFile(manifest)
+	if err != nil || !bytes.Equal(data, []byte(b.digest)) {
+		return false
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(manifest, now, now)
+
+	return true
+}
+
+// write writes the files of the Structurizr CLI into dir
+func (b *bundle) write(dir string) error {
+	return fs.WalkDir(b.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		dest := filepath.Join(dir, filepath.FromSlash(name))
+		if d.IsDir() {
+			return os.MkdirAll(dest, 0o755)
+		}
+
+		data, err := fs.ReadFile(b.fsys, name)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(dest, data, os.ModePerm)
+	})
+}
+
+// verify compares the files written into dir with the checksums of the embedded files
+func (b *bundle) verify(dir string) error {
+	for name, expected := range b.sums {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+
+		if sha256.Sum256(data) != expected {
+			return fmt.Errorf("checksum mismatch of %s", path.Base(name))
+		}
+	}
+
+	return nil
+}
+
+// removeStale removes the other versions of the Structurizr CLI, and the leftovers of interrupted extractions,
+// which have not been used for a while. Versions used recently may still be run by another Terraform process.
+func removeStale(ctx context.Context, baseDir string, current string, now time.Time) {
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || name == current ||
+			!(strings.HasPrefix(name, dirPrefix) || strings.HasPrefix(name, "."+dirPrefix)) {
+			continue
+		}
+
+		dir := filepath.Join(baseDir, name)
+		if lastUsed(dir).After(now.Add(-staleAfter)) {
+			continue
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("Removing unused Structurizr CLI directory: %s", dir))
+
+		if err = os.RemoveAll(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			tflog.Warn(ctx, fmt.Sprintf("Failed to remove unused Structurizr CLI directory %s: %v", dir, err))
+		}
+	}
+}
+
+// lastUsed returns when a version of the Structurizr CLI was last used, or when its directory was last modified
+func lastUsed(dir string) time.Time {
+	if fi, err := os.Stat(filepath.Join(dir, manifestName)); err == nil {
+		return fi.ModTime()
+	}
+
+	if fi, err := os.Stat(dir); err == nil {
+		return fi.ModTime()
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func newTestBundle(t *testing.T, version string) *bundle {
+	b, err := newBundle(fstest.MapFS{
+		"version.txt":     {Data: []byte(version + "\n")},
+		"structurizr.sh":  {Data: []byte("#!/bin/sh\necho " + version)},
+		"lib/cli.jar":     {Data: []byte("jar " + version)},
+		"structurizr.bat": {Data: []byte("@echo " + version)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestBundle_Extract(t *testing.T) {
+	baseDir := t.TempDir()
+	b := newTestBundle(t, "v2024.03.03")
+
+	dir, err := b.extract(context.TODO(), baseDir)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(filepath.Base(dir), "structurizr-cli-v2024.03.03-"))
+	data, err := os.ReadFile(filepath.Join(dir, "lib", "cli.jar"))
+	assert.NoError(t, err)
+	assert.Equal(t, "jar v2024.03.03", string(data))
+
+	// Only the extracted version remains, without temporary directories
+	entries, _ := os.ReadDir(baseDir)
+	assert.Len(t, entries, 1)
+
+	t.Run("Given the version is already extracted", func(t *testing.T) {
+		actual, err := b.extract(context.TODO(), baseDir)
+
+		assert.NoError(t, err)
+		assert.Equal(t, dir, actual)
+	})
+	t.Run("Given another version", func(t *testing.T) {
+		actual, err := newTestBundle(t, "v2024.07.02").extract(context.TODO(), baseDir)
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, dir, actual)
+	})
+	t.Run("Given an incomplete extraction", func(t *testing.T) {
+		assert.NoError(t, os.Remove(filepath.Join(dir, manifestName)))
+		assert.NoError(t, os.Remove(filepath.Join(dir, "lib", "cli.jar")))
+
+		actual, err := b.extract(context.TODO(), baseDir)
+
+		assert.NoError(t, err)
+		assert.Equal(t, dir, actual)
+		assert.FileExists(t, filepath.Join(dir, "lib", "cli.jar"))
+	})
+}
+
+func TestBundle_Extract_Concurrent(t *testing.T) {
+	baseDir := t.TempDir()
+	b := newTestBundle(t, "v2024.03.03")
+
+	var wg sync.WaitGroup
+	dirs := make([]string, 8)
+	errs := make([]error, 8)
+	for i := range dirs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dirs[i], errs[i] = b.extract(context.TODO(), baseDir)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range dirs {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, dirs[0], dirs[i])
+	}
+	assert.FileExists(t, filepath.Join(dirs[0], "structurizr.sh"))
+}
+
+func TestBundle_Verify(t *testing.T) {
+	dir := t.TempDir()
+	b := newTestBundle(t, "v2024.03.03")
+	assert.NoError(t, b.write(dir))
+	assert.NoError(t, b.verify(dir))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "lib", "cli.jar"), []byte("tampered"), 0o644))
+
+	assert.ErrorContains(t, b.verify(dir), "checksum mismatch of cli.jar")
+}
+
+func TestRemoveStale(t *testing.T) {
+	baseDir := t.TempDir()
+	now := time.Now()
+
+	current, _ := newTestBundle(t, "v2024.07.02").extract(context.TODO(), baseDir)
+	stale, _ := newTestBundle(t, "v2024.03.03").extract(context.TODO(), baseDir)
+	recent, _ := newTestBundle(t, "v2024.05.01").extract(context.TODO(), baseDir)
+	unrelated := filepath.Join(baseDir, "other")
+	assert.NoError(t, os.Mkdir(unrelated, 0o755))
+
+	old := now.Add(-2 * staleAfter)
+	assert.NoError(t, os.Chtimes(filepath.Join(current, manifestName), old, old))
+	assert.NoError(t, os.Chtimes(filepath.Join(stale, manifestName), old, old))
+	assert.NoError(t, os.Chtimes(unrelated, old, old))
+
+	removeStale(context.TODO(), baseDir, filepath.Base(current), now)
+
+	assert.DirExists(t, current)
+	assert.NoDirExists(t, stale)
+	assert.DirExists(t, recent)
+	assert.DirExists(t, unrelated)
+}
+
+func TestWorkingDir(t *testing.T) {
+	baseDir := t.TempDir()
+
+	dir, err := WorkingDir(context.TODO(), baseDir)
+
+	assert.NoError(t, err)
+	assert.Equal(t, baseDir, filepath.Dir(dir))
+	assert.Contains(t, filepath.Base(dir), Version())
+	assert.FileExists(t, filepath.Join(dir, "structurizr.sh"))
+}
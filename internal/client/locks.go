@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultMaxParallelOperations is the default number of operations run in parallel against the remote server
+const DefaultMaxParallelOperations = 4
+
+// workspaceLocks serializes the operations on the same workspace, while operations on different workspaces
+// run in parallel. Waiting for a lock is cancelled with the context, such as when the operation times out.
+type workspaceLocks struct {
+	mu    sync.Mutex
+	locks map[int64]*workspaceLock
+}
+
+// workspaceLock is the lock of a workspace, it is kept for as long as operations hold or wait for it
+type workspaceLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// lock locks the workspace and returns the function to unlock it
+func (l *workspaceLocks) lock(ctx context.Context, id int64) (func(), error) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[int64]*workspaceLock{}
+	}
+
+	k, ok := l.locks[id]
+	if !ok {
+		k = &workspaceLock{ch: make(chan struct{}, 1)}
+		l.locks[id] = k
+	}
+	k.refs++
+	l.mu.Unlock()
+
+	select {
+	case k.ch <- struct{}{}:
+		return func() {
+			<-k.ch
+			l.release(id, k)
+		}, nil
+	case <-ctx.Done():
+		l.release(id, k)
+		return nil, ctx.Err()
+	}
+}
+
+// release forgets the lock of a workspace once no operation holds or waits for it
+func (l *workspaceLocks) release(id int64, k *workspaceLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	k.refs--
+	if k.refs == 0 {
+		delete(l.locks, id)
+	}
+}
+
+// semaphore limits the number of operations run in parallel, a nil semaphore is unlimited
+type semaphore chan struct{}
+
+// newSemaphore returns a semaphore allowing n operations in parallel, or an unlimited one when n is not positive
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+
+	return make(semaphore, n)
+}
+
+// acquire waits for a free slot and returns the function to release it
+func (s semaphore) acquire(ctx context.Context) (func(), error) {
+	if s == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case s <- struct{}{}:
+		return func() { <-s }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// running records the number of operations running at the same time
+type running struct {
+	current atomic.Int32
+	max     atomic.Int32
+}
+
+// run simulates an operation
+func (r *running) run() {
+	n := r.current.Add(1)
+	for {
+		m := r.max.Load()
+		if n <= m || r.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	r.current.Add(-1)
+}
+
+func TestWorkspaceLocks(t *testing.T) {
+	t.Run("Given operations on the same workspace", func(t *testing.T) {
+		locks, r := &workspaceLocks{}, &running{}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock, err := locks.lock(context.Background(), 1)
+				assert.NoError(t, err)
+				defer unlock()
+				r.run()
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), r.max.Load())
+		assert.Empty(t, locks.locks)
+	})
+	t.Run("Given operations on different workspaces", func(t *testing.T) {
+		locks, r := &workspaceLocks{}, &running{}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(id int64) {
+				defer wg.Done()
+				unlock, err := locks.lock(context.Background(), id)
+				assert.NoError(t, err)
+				defer unlock()
+				r.run()
+			}(int64(i))
+		}
+		wg.Wait()
+
+		assert.Greater(t, r.max.Load(), int32(1))
+		assert.Empty(t, locks.locks)
+	})
+	t.Run("Given the context is done while waiting", func(t *testing.T) {
+		locks := &workspaceLocks{}
+		unlock, err := locks.lock(context.Background(), 1)
+		assert.NoError(t, err)
+		defer unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = locks.lock(ctx, 1)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, locks.locks[1].refs)
+	})
+}
+
+func TestSemaphore(t *testing.T) {
+	t.Run("Given a limit", func(t *testing.T) {
+		s, r := newSemaphore(2), &running{}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release, err := s.acquire(context.Background())
+				assert.NoError(t, err)
+				defer release()
+				r.run()
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(2), r.max.Load())
+	})
+	t.Run("Given no limit", func(t *testing.T) {
+		s := newSemaphore(0)
+
+		release, err := s.acquire(context.Background())
+
+		assert.Nil(t, s)
+		assert.NoError(t, err)
+		release()
+	})
+	t.Run("Given the context is done while waiting", func(t *testing.T) {
+		s := newSemaphore(1)
+		release, _ := s.acquire(context.Background())
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := s.acquire(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -2,48 +2,274 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api"
 	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/cli"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/crypto"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure the clients satisfy the expected interfaces.
+var (
+	_ WorkspacesClient   = (*api.Client)(nil)
+	_ WorkspaceClient    = (*api.Client)(nil)
+	_ WorkspaceClient    = (*cli.Client)(nil)
+	_ WorkspaceValidator = (*cli.Client)(nil)
 )
 
 type WorkspacesClient interface {
 	GetWorkspaces(ctx context.Context) (*model.Workspaces, error)
 	CreateWorkspace(ctx context.Context) (*model.Workspace, error)
 	DeleteWorkspace(ctx context.Context, id int64) (*model.APIResponse, error)
+	GetWorkspace(ctx context.Context, id int64, key string, secret string) (*model.WorkspaceContent, error)
+	GetWorkspaceVersions(ctx context.Context, id int64, key string, secret string) (*model.WorkspaceVersions, error)
+	RestoreWorkspaceVersion(ctx context.Context, id int64, key string, secret string, version string) error
+	LockWorkspace(ctx context.Context, id int64, key string, secret string, user string, agent string) (*model.APIResponse, error)
+	UnlockWorkspace(ctx context.Context, id int64, key string, secret string, user string, agent string) (*model.APIResponse, error)
 }
 
+// WorkspaceClient pushes workspace sources either through the Structurizr CLI (cli.Client)
+// or straight to the Structurizr workspace API (api.Client)
 type WorkspaceClient interface {
 	// PushWorkspace push a new version of a workspace from an existing file
-	PushWorkspace(ctx context.Context, id int64, key string, secret string, passphrase string, source string) error
+	PushWorkspace(
+		ctx context.Context,
+		id int64,
+		key string,
+		secret string,
+		passphrase string,
+		source string,
+		options api.PushOptions,
+	) error
+}
+
+// WorkspaceValidator validates workspace sources before they are pushed, only the Structurizr CLI (cli.Client) can
+type WorkspaceValidator interface {
+	// ValidateWorkspace validates a workspace source without pushing it
+	ValidateWorkspace(ctx context.Context, source string) error
+	// InspectWorkspace returns the errors and warnings found by the inspection of a workspace source
+	InspectWorkspace(ctx context.Context, source string) ([]cli.Finding, error)
+}
+
+// DefaultArchiveRetention is the default number of archives kept per workspace
+const DefaultArchiveRetention = 10
+
+// PushConfig is the configuration of the pushes of workspaces shared by all resources
+type PushConfig struct {
+	// Defaults are the options of the pushes which do not override them
+	Defaults api.PushOptions
+	// ArchiveRetention is the number of archives kept per workspace, all of them are kept when it is not positive
+	ArchiveRetention int
+	// Validate validates the sources of the workspaces at plan time, before they are pushed
+	Validate bool
 }
 
 // Manager is managing the required clients to interact with Structurizr.
+// It runs at most a given number of operations in parallel, and serializes the operations modifying the same workspace.
+// The list of workspaces is cached for a short time and invalidated by every mutation.
 // Use NewManager to get started
 type Manager struct {
-	api WorkspacesClient
-	cli WorkspaceClient
+	api        WorkspacesClient
+	cli        WorkspaceClient
+	locks      *workspaceLocks
+	operations semaphore
+	workspaces *workspacesCache
+	push       PushConfig
+}
+
+// NewManager creates a new Manager with the required clients to interact with Structurizr,
+// running at most maxParallelOperations operations in parallel, or unlimited when it is not positive
+func NewManager(api WorkspacesClient, cli WorkspaceClient, maxParallelOperations int, push PushConfig) *Manager {
+	return &Manager{
+		api:        api,
+		cli:        cli,
+		locks:      &workspaceLocks{},
+		operations: newSemaphore(maxParallelOperations),
+		workspaces: &workspacesCache{ttl: DefaultWorkspacesCacheTTL},
+		push:       push,
+	}
+}
+
+// PushDefaults returns the default options of the pushes of workspaces
+func (m *Manager) PushDefaults() api.PushOptions {
+	return m.push.Defaults
+}
+
+// Validator returns the validator of the sources of the workspaces, false when their validation is disabled
+// or not supported by the push client
+func (m *Manager) Validator() (WorkspaceValidator, bool) {
+	if !m.push.Validate {
+		return nil, false
+	}
+
+	v, ok := m.cli.(WorkspaceValidator)
+	return v, ok
+}
+
+// acquire waits for the operation to run within the limit of parallel operations.
+// It returns the function to call once the operation is done.
+func (m *Manager) acquire(ctx context.Context) (func(), error) {
+	return m.operations.acquire(ctx)
 }
 
-// NewManager creates a new Manager with the required clients to interact with Structurizr
-func NewManager(api WorkspacesClient, cli WorkspaceClient) *Manager {
-	return &Manager{api, cli}
+// acquireWorkspace waits for the operation modifying a workspace to run, once no other operation modifies it and
+// within the limit of parallel operations. It returns the function to call once the operation is done.
+func (m *Manager) acquireWorkspace(ctx context.Context, id int64) (func(), error) {
+	// Locking the workspace first, so operations waiting for it do not hold a slot of the parallel operations
+	unlock, err := m.locks.lock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := m.acquire(ctx)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+
+	return func() {
+		release()
+		unlock()
+	}, nil
 }
 
-// GetWorkspaces lists all workspaces
+// GetWorkspaces lists all workspaces, the list is shared by concurrent calls and cached for a short time
 func (m *Manager) GetWorkspaces(ctx context.Context) (*model.Workspaces, error) {
-	return m.api.GetWorkspaces(ctx)
+	return m.workspaces.get(ctx, func(ctx context.Context) (*model.Workspaces, error) {
+		release, err := m.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return m.api.GetWorkspaces(ctx)
+	})
 }
 
 // CreateWorkspace creates a new workspace
 func (m *Manager) CreateWorkspace(ctx context.Context) (*model.Workspace, error) {
+	release, err := m.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer m.workspaces.invalidate()
+
 	return m.api.CreateWorkspace(ctx)
 }
 
 // DeleteWorkspace deletes a workspace
 func (m *Manager) DeleteWorkspace(ctx context.Context, id int64) (*model.APIResponse, error) {
+	release, err := m.acquireWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer m.workspaces.invalidate()
+
 	return m.api.DeleteWorkspace(ctx, id)
 }
 
-// PushWorkspace push a new version of a workspace from an existing file
+// GetWorkspace retrieves the content of a workspace, which is decrypted when a passphrase is provided
+func (m *Manager) GetWorkspace(
+	ctx context.Context,
+	id int64,
+	key string,
+	secret string,
+	passphrase string,
+) (*model.WorkspaceContent, error) {
+	release, err := m.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	content, err := m.api.GetWorkspace(ctx, id, key, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if passphrase == "" || !content.IsEncrypted() {
+		return content, nil
+	}
+
+	plaintext, err := crypto.Open(content.Raw, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt workspace (id: %d): %w", id, err)
+	}
+
+	content.Raw = plaintext
+
+	return content, nil
+}
+
+// GetWorkspaceVersions lists the previous versions of a workspace
+func (m *Manager) GetWorkspaceVersions(
+	ctx context.Context,
+	id int64,
+	key string,
+	secret string,
+) (*model.WorkspaceVersions, error) {
+	release, err := m.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return m.api.GetWorkspaceVersions(ctx, id, key, secret)
+}
+
+// RestoreWorkspaceVersion restores a previous version of a workspace as its latest version
+func (m *Manager) RestoreWorkspaceVersion(ctx context.Context, id int64, key string, secret string, version string) error {
+	release, err := m.acquireWorkspace(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer release()
+	defer m.workspaces.invalidate()
+
+	return m.api.RestoreWorkspaceVersion(ctx, id, key, secret, version)
+}
+
+// LockWorkspace locks a workspace on behalf of the given user and agent
+func (m *Manager) LockWorkspace(
+	ctx context.Context,
+	id int64,
+	key string,
+	secret string,
+	user string,
+	agent string,
+) (*model.APIResponse, error) {
+	release, err := m.acquireWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return m.api.LockWorkspace(ctx, id, key, secret, user, agent)
+}
+
+// UnlockWorkspace unlocks a workspace previously locked by the given user and agent
+func (m *Manager) UnlockWorkspace(
+	ctx context.Context,
+	id int64,
+	key string,
+	secret string,
+	user string,
+	agent string,
+) (*model.APIResponse, error) {
+	release, err := m.acquireWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return m.api.UnlockWorkspace(ctx, id, key, secret, user, agent)
+}
+
+// PushWorkspace push a new version of a workspace from an existing file.
+// Once pushed, only the most recent archives of the workspace are kept.
 func (m *Manager) PushWorkspace(
 	ctx context.Context,
 	id int64,
@@ -51,6 +277,24 @@ func (m *Manager) PushWorkspace(
 	secret string,
 	passphrase string,
 	source string,
+	options api.PushOptions,
 ) error {
-	return m.cli.PushWorkspace(ctx, id, key, secret, passphrase, source)
+	release, err := m.acquireWorkspace(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer release()
+	defer m.workspaces.invalidate()
+
+	if err = m.cli.PushWorkspace(ctx, id, key, secret, passphrase, source, options); err != nil {
+		return err
+	}
+
+	if options.Archive && m.push.ArchiveRetention > 0 {
+		if err = api.PruneArchives(options.ArchiveDir, id, m.push.ArchiveRetention); err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Failed to remove the oldest archives of Workspace (id: %d): %v", id, err))
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/cli"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runningWorkspaceClient is a WorkspaceClient recording how many pushes run at the same time
+type runningWorkspaceClient struct {
+	running
+}
+
+// PushWorkspace simulates a push
+func (c *runningWorkspaceClient) PushWorkspace(_ context.Context, _ int64, _, _, _, _ string, _ api.PushOptions) error {
+	c.run()
+	return nil
+}
+
+// archivingWorkspaceClient is a WorkspaceClient archiving the workspace on every push, as the Structurizr CLI does
+type archivingWorkspaceClient struct {
+	pushes int
+}
+
+// PushWorkspace simulates a push writing an archive
+func (c *archivingWorkspaceClient) PushWorkspace(
+	_ context.Context,
+	id int64,
+	_, _, _, _ string,
+	options api.PushOptions,
+) error {
+	c.pushes++
+	if !options.Archive {
+		return nil
+	}
+
+	name := api.ArchiveName(id, time.Date(2024, 5, 1, 10, 0, c.pushes, 0, time.UTC))
+	return os.WriteFile(filepath.Join(options.ArchiveDir, name), nil, 0o600)
+}
+
+func TestManager_PushWorkspace_ArchiveRetention(t *testing.T) {
+	tests := []struct {
+		name      string
+		archive   bool
+		retention int
+		expected  int
+	}{
+		{"Given a retention", true, 2, 2},
+		{"Given no retention", true, 0, 5},
+		{"Given no archive", false, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			options := api.PushOptions{Archive: tt.archive, ArchiveDir: dir}
+			m := NewManager(nil, &archivingWorkspaceClient{}, 1, PushConfig{Defaults: options, ArchiveRetention: tt.retention})
+
+			for i := 0; i < 5; i++ {
+				assert.NoError(t, m.PushWorkspace(context.Background(), 1, "key", "secret", "", "workspace.dsl", options))
+			}
+
+			archives, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+			assert.Len(t, archives, tt.expected)
+			assert.Equal(t, options, m.PushDefaults())
+		})
+	}
+}
+
+func TestManager_PushWorkspace(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxParallel int
+		ids         []int64
+		expectedMax int32
+	}{
+		{"Given pushes of the same workspace", 4, []int64{1, 1, 1, 1}, 1},
+		{"Given pushes of different workspaces", 2, []int64{1, 2, 3, 4, 5, 6}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &runningWorkspaceClient{}
+			m := NewManager(nil, cli, tt.maxParallel, PushConfig{})
+
+			var wg sync.WaitGroup
+			for _, id := range tt.ids {
+				wg.Add(1)
+				go func(id int64) {
+					defer wg.Done()
+					assert.NoError(t, m.PushWorkspace(context.Background(), id, "key", "secret", "", "workspace.dsl", api.PushOptions{}))
+				}(id)
+			}
+			wg.Wait()
+
+			assert.Equal(t, tt.expectedMax, cli.max.Load())
+		})
+	}
+}
+
+func TestManager_Validator(t *testing.T) {
+	validator := cli.NewClient(&cli.Config{}, cli.DefaultCmdExec)
+
+	tests := []struct {
+		name     string
+		client   WorkspaceClient
+		validate bool
+		expected bool
+	}{
+		{"Given the Structurizr CLI", validator, true, true},
+		{"Given the Structurizr CLI without validation", validator, false, false},
+		{"Given a push client without validation support", &runningWorkspaceClient{}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(nil, tt.client, 1, PushConfig{Validate: tt.validate})
+
+			_, ok := m.Validator()
+
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
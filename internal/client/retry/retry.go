@@ -0,0 +1,55 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the default number of retries after the initial attempt
+	DefaultMaxRetries = 3
+	// DefaultMinWait is the default wait before the first retry
+	DefaultMinWait = 1 * time.Second
+	// DefaultMaxWait is the default upper bound of the wait between two retries
+	DefaultMaxWait = 30 * time.Second
+)
+
+// Policy describes how many times and how long to wait before retrying a failed operation
+type Policy struct {
+	MaxRetries int
+	MinWait    time.Duration
+	MaxWait    time.Duration
+}
+
+// Backoff returns the wait before the given retry attempt (starting at 1), doubling on every attempt
+// from MinWait and never exceeding MaxWait
+func (p Policy) Backoff(attempt int) time.Duration {
+	wait := p.MinWait
+	for i := 1; i < attempt && wait < p.MaxWait; i++ {
+		wait *= 2
+	}
+
+	return p.Cap(wait)
+}
+
+// Cap bounds a wait to MaxWait, it is mostly used for waits imposed by the remote server
+func (p Policy) Cap(wait time.Duration) time.Duration {
+	if p.MaxWait > 0 && wait > p.MaxWait {
+		return p.MaxWait
+	}
+
+	return wait
+}
+
+// Wait blocks for the given duration or until the context is done
+func Wait(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
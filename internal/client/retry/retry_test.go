@@ -0,0 +1,49 @@
+package retry
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPolicy_Backoff(t *testing.T) {
+	p := Policy{MaxRetries: 5, MinWait: time.Second, MaxWait: 5 * time.Second}
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{60, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, p.Backoff(tt.attempt), "attempt %d", tt.attempt)
+	}
+}
+
+func TestPolicy_Cap(t *testing.T) {
+	t.Run("Given a maximum wait", func(t *testing.T) {
+		p := Policy{MaxWait: 5 * time.Second}
+		assert.Equal(t, 5*time.Second, p.Cap(time.Minute))
+		assert.Equal(t, time.Second, p.Cap(time.Second))
+	})
+	t.Run("Given no maximum wait", func(t *testing.T) {
+		assert.Equal(t, time.Minute, Policy{}.Cap(time.Minute))
+	})
+}
+
+func TestWait(t *testing.T) {
+	t.Run("Given an elapsed wait", func(t *testing.T) {
+		assert.NoError(t, Wait(context.Background(), time.Millisecond))
+	})
+	t.Run("Given a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, Wait(ctx, time.Minute), context.Canceled)
+	})
+}
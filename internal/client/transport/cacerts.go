@@ -0,0 +1,187 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const (
+	// javaDefaultStorePassword is the well-known password of the default trust store of the JVM
+	javaDefaultStorePassword = "changeit"
+	// jksMagic starts every Java KeyStore (JKS) file
+	jksMagic = 0xfeedfeed
+)
+
+// javaCACertificates returns the certificates of the default trust store of the JVM running the Structurizr CLI,
+// found from the JAVA_HOME environment variable, otherwise from the java executable in the PATH.
+// No certificates are returned when no JVM is found.
+func javaCACertificates() ([]*x509.Certificate, error) {
+	name, err := javaTrustStore()
+	if err != nil || name == "" {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	certs, err := decodeJavaTrustStore(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the default trust store of the JVM %s: %w", name, err)
+	}
+
+	return certs, nil
+}
+
+// javaTrustStore returns the path of the default trust store (cacerts) of the JVM, empty when none is found
+func javaTrustStore() (string, error) {
+	home := os.Getenv("JAVA_HOME")
+	if home == "" {
+		java, err := exec.LookPath("java")
+		if err != nil {
+			return "", nil
+		}
+
+		// The java executable is usually a link, e.g. /usr/bin/java -> /usr/lib/jvm/<jdk>/bin/java
+		if java, err = filepath.EvalSymlinks(java); err != nil {
+			return "", err
+		}
+
+		home = filepath.Dir(filepath.Dir(java))
+	}
+
+	// The trust store of Java 8 is within the JRE of the JDK
+	for _, name := range []string{
+		filepath.Join(home, "lib", "security", "cacerts"),
+		filepath.Join(home, "jre", "lib", "security", "cacerts"),
+	} {
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// decodeJavaTrustStore decodes a trust store of the JVM, which is a password-less PKCS#12 file since Java 18,
+// a PKCS#12 file protected by the default password, or a JKS file before
+func decodeJavaTrustStore(data []byte) ([]*x509.Certificate, error) {
+	if len(data) >= 4 && binary.BigEndian.Uint32(data) == jksMagic {
+		return decodeJKSTrustStore(data)
+	}
+
+	certs, err := pkcs12.DecodeTrustStore(data, "")
+	if err != nil {
+		certs, err = pkcs12.DecodeTrustStore(data, javaDefaultStorePassword)
+	}
+
+	return certs, err
+}
+
+// decodeJKSTrustStore returns the trusted certificates of a JKS file, its private keys are skipped.
+// The integrity of the file is not verified, as it is only read from the local JVM.
+func decodeJKSTrustStore(data []byte) ([]*x509.Certificate, error) {
+	r := bytes.NewReader(data)
+
+	var header struct{ Magic, Version, Count uint32 }
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, err
+	}
+
+	if header.Version != 1 && header.Version != 2 {
+		return nil, fmt.Errorf("unsupported JKS version: %d", header.Version)
+	}
+
+	var certs []*x509.Certificate
+	for i := uint32(0); i < header.Count; i++ {
+		var tag uint32
+		if err := binary.Read(r, binary.BigEndian, &tag); err != nil {
+			return nil, err
+		}
+
+		// The alias and the creation date of the entry
+		if _, err := readJKSBytes(r, 2); err != nil {
+			return nil, err
+		}
+		if _, err := r.Seek(8, io.SeekCurrent); err != nil {
+			return nil, err
+		}
+
+		switch tag {
+		case 1:
+			if _, err := readJKSBytes(r, 4); err != nil {
+				return nil, err
+			}
+
+			var chain uint32
+			if err := binary.Read(r, binary.BigEndian, &chain); err != nil {
+				return nil, err
+			}
+
+			for j := uint32(0); j < chain; j++ {
+				if _, err := readJKSCertificate(r, header.Version); err != nil {
+					return nil, err
+				}
+			}
+		case 2:
+			der, err := readJKSCertificate(r, header.Version)
+			if err != nil {
+				return nil, err
+			}
+
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, err
+			}
+
+			certs = append(certs, cert)
+		default:
+			return nil, fmt.Errorf("unsupported JKS entry: %d", tag)
+		}
+	}
+
+	return certs, nil
+}
+
+// readJKSCertificate reads a certificate of a JKS entry, preceded by its type since the version 2
+func readJKSCertificate(r *bytes.Reader, version uint32) ([]byte, error) {
+	if version == 2 {
+		if _, err := readJKSBytes(r, 2); err != nil {
+			return nil, err
+		}
+	}
+
+	return readJKSBytes(r, 4)
+}
+
+// readJKSBytes reads a value preceded by its length, encoded on size bytes
+func readJKSBytes(r *bytes.Reader, size int) ([]byte, error) {
+	var n uint32
+	if size == 2 {
+		var n16 uint16
+		if err := binary.Read(r, binary.BigEndian, &n16); err != nil {
+			return nil, err
+		}
+		n = uint32(n16)
+	} else if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+
+	if int64(n) > int64(r.Len()) {
+		return nil, errors.New("truncated JKS file")
+	}
+
+	b := make([]byte, n)
+	_, err := io.ReadFull(r, b)
+
+	return b, err
+}
@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"software.sslmate.com/src/go-pkcs12"
+	"testing"
+)
+
+func TestJavaCACertificates(t *testing.T) {
+	tests := []struct {
+		name       string
+		cacerts    string
+		trustStore func(t *testing.T, commonName string) []byte
+		expected   []string
+		wantError  bool
+	}{
+		{"Given a PKCS#12 trust store", filepath.Join("lib", "security", "cacerts"), pkcs12TrustStore, []string{"Public CA"}, false},
+		{"Given a JKS trust store", filepath.Join("lib", "security", "cacerts"), jksTrustStore, []string{"Public CA"}, false},
+		{"Given a Java 8 trust store", filepath.Join("jre", "lib", "security", "cacerts"), jksTrustStore, []string{"Public CA"}, false},
+		{"Given no trust store", "", nil, nil, false},
+		{"Given an invalid trust store", filepath.Join("lib", "security", "cacerts"), func(*testing.T, string) []byte {
+			return []byte("invalid")
+		}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("JAVA_HOME", home)
+			if tt.cacerts != "" {
+				writeJavaTrustStore(t, filepath.Join(home, tt.cacerts), tt.trustStore(t, "Public CA"))
+			}
+
+			certs, err := javaCACertificates()
+
+			assert.Equal(t, tt.wantError, err != nil)
+			var names []string
+			for _, cert := range certs {
+				names = append(names, cert.Subject.CommonName)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+// pkcs12TrustStore returns a password-less PKCS#12 trust store, as the default one of Java 18 and newer
+func pkcs12TrustStore(t *testing.T, commonName string) []byte {
+	t.Helper()
+
+	data, err := pkcs12.Passwordless.EncodeTrustStore([]*x509.Certificate{parseCertificate(t, commonName)}, "")
+	assert.NoError(t, err)
+
+	return data
+}
+
+// jksTrustStore returns a JKS trust store, as the default one of Java 17 and older
+func jksTrustStore(t *testing.T, commonName string) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	write := func(v any) { assert.NoError(t, binary.Write(&b, binary.BigEndian, v)) }
+	writeString := func(s string) { write(uint16(len(s))); b.WriteString(s) }
+
+	der := parseCertificate(t, commonName).Raw
+	write(uint32(jksMagic))
+	write(uint32(2))
+	write(uint32(1))
+	write(uint32(2))
+	writeString("publicca")
+	write(uint64(0))
+	writeString("X.509")
+	write(uint32(len(der)))
+	b.Write(der)
+	// The integrity digest of the file, which is not verified
+	b.Write(make([]byte, 20))
+
+	return b.Bytes()
+}
+
+// writeJavaTrustStore writes a default trust store of a JVM
+func writeJavaTrustStore(t *testing.T, name string, data []byte) {
+	t.Helper()
+
+	assert.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	assert.NoError(t, os.WriteFile(name, data, 0o644))
+}
+
+// parseCertificate returns a new certificate
+func parseCertificate(t *testing.T, commonName string) *x509.Certificate {
+	t.Helper()
+
+	data, _ := newCertificate(t, commonName)
+	certs, err := parseCertificates(data)
+	assert.NoError(t, err)
+
+	return certs[0]
+}
@@ -0,0 +1,202 @@
+package transport
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"software.sslmate.com/src/go-pkcs12"
+	"strings"
+)
+
+const (
+	trustStoreFile = "truststore.p12"
+	keyStoreFile   = "keystore.p12"
+)
+
+// HasJavaOptions reports whether the configuration has to be passed to the JVM of the Structurizr CLI
+func (c Config) HasJavaOptions() bool {
+	return len(c.CABundle) > 0 || len(c.ClientCertificate) > 0 || c.ProxyURL != nil
+}
+
+// JavaOptions returns the JVM system properties applying the configuration to the Structurizr CLI.
+// The CA bundle and the client certificate are written as PKCS#12 stores to dir, which should only be readable
+// by the current user and removed once the CLI exits. As for the API client, the CA bundle is trusted in addition
+// to the default trust store of the JVM, which the written trust store replaces.
+func (c Config) JavaOptions(dir string) ([]string, error) {
+	var options []string
+
+	if len(c.CABundle) > 0 {
+		certs, err := parseCertificates(c.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA bundle: %w", err)
+		}
+
+		defaults, err := javaCACertificates()
+		if err != nil {
+			return nil, err
+		}
+
+		// Certificates sharing a subject get their own alias, otherwise the JVM only keeps one of them
+		entries := make([]pkcs12.TrustStoreEntry, 0, len(certs)+len(defaults))
+		for i, cert := range append(certs, defaults...) {
+			entries = append(entries, pkcs12.TrustStoreEntry{Cert: cert, FriendlyName: fmt.Sprintf("ca-%d", i)})
+		}
+
+		password, err := storePassword()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := pkcs12.Modern.EncodeTrustStoreEntries(entries, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the trust store: %w", err)
+		}
+
+		path := filepath.Join(dir, trustStoreFile)
+		if err = os.WriteFile(path, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write the trust store: %w", err)
+		}
+
+		options = append(options,
+			javaProperty("javax.net.ssl.trustStore", path),
+			javaProperty("javax.net.ssl.trustStoreType", "PKCS12"),
+			javaProperty("javax.net.ssl.trustStorePassword", password),
+		)
+	}
+
+	if len(c.ClientCertificate) > 0 {
+		cert, err := tls.X509KeyPair(c.ClientCertificate, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+
+		chain := make([]*x509.Certificate, len(cert.Certificate))
+		for i, der := range cert.Certificate {
+			if chain[i], err = x509.ParseCertificate(der); err != nil {
+				return nil, fmt.Errorf("invalid client certificate: %w", err)
+			}
+		}
+
+		password, err := storePassword()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := pkcs12.Modern.Encode(cert.PrivateKey, chain[0], chain[1:], password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the key store: %w", err)
+		}
+
+		path := filepath.Join(dir, keyStoreFile)
+		if err = os.WriteFile(path, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write the key store: %w", err)
+		}
+
+		options = append(options,
+			javaProperty("javax.net.ssl.keyStore", path),
+			javaProperty("javax.net.ssl.keyStoreType", "PKCS12"),
+			javaProperty("javax.net.ssl.keyStorePassword", password),
+		)
+	}
+
+	if c.ProxyURL != nil {
+		host, port := c.ProxyURL.Hostname(), c.ProxyURL.Port()
+		if port == "" {
+			port = "80"
+			if c.ProxyURL.Scheme == "https" {
+				port = "443"
+			}
+		}
+
+		options = append(options,
+			javaProperty("http.proxyHost", host),
+			javaProperty("http.proxyPort", port),
+			javaProperty("https.proxyHost", host),
+			javaProperty("https.proxyPort", port),
+		)
+
+		if nonProxyHosts := javaNonProxyHosts(c.NoProxy); nonProxyHosts != "" {
+			options = append(options, javaProperty("http.nonProxyHosts", nonProxyHosts))
+		}
+	}
+
+	return options, nil
+}
+
+// javaNonProxyHosts converts a NO_PROXY list to the format of the http.nonProxyHosts JVM system property.
+// CIDR ranges are not supported by the JVM and are ignored.
+func javaNonProxyHosts(noProxy string) string {
+	var hosts []string
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if entry == "*" {
+			return "*"
+		}
+
+		if _, _, err := net.ParseCIDR(entry); err == nil {
+			continue
+		}
+
+		if host, _, err := net.SplitHostPort(entry); err == nil {
+			entry = host
+		}
+
+		// NO_PROXY entries match the domain and its subdomains
+		entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		hosts = append(hosts, entry, "*."+entry)
+	}
+
+	return strings.Join(hosts, "|")
+}
+
+// javaProperty formats a JVM system property, quoting values with spaces as options are split on whitespaces
+func javaProperty(name string, value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return fmt.Sprintf(`"-D%s=%s"`, name, value)
+	}
+
+	return fmt.Sprintf("-D%s=%s", name, value)
+}
+
+// parseCertificates parses all PEM encoded certificates
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+
+	return certs, nil
+}
+
+// storePassword generates a random password protecting a PKCS#12 store for the lifetime of a CLI execution
+func storePassword() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
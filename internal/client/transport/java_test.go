@@ -0,0 +1,133 @@
+package transport
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"os"
+	"path/filepath"
+	"software.sslmate.com/src/go-pkcs12"
+	"strings"
+	"testing"
+)
+
+func TestJavaOptions(t *testing.T) {
+	ca, _ := newCertificate(t, "Internal CA")
+	cert, key := newCertificate(t, "terraform")
+	proxyURL, _ := url.Parse("http://proxy.internal:3128")
+
+	t.Run("Given no configuration", func(t *testing.T) {
+		config := Config{TLSInsecure: true}
+
+		options, err := config.JavaOptions(t.TempDir())
+
+		assert.NoError(t, err)
+		assert.Empty(t, options)
+		assert.False(t, config.HasJavaOptions())
+	})
+	t.Run("Given a CA bundle", func(t *testing.T) {
+		t.Setenv("JAVA_HOME", t.TempDir())
+		dir := t.TempDir()
+		config := Config{CABundle: ca}
+
+		options, err := config.JavaOptions(dir)
+
+		assert.NoError(t, err)
+		assert.True(t, config.HasJavaOptions())
+		assert.Contains(t, options, "-Djavax.net.ssl.trustStore="+filepath.Join(dir, trustStoreFile))
+		assert.Contains(t, options, "-Djavax.net.ssl.trustStoreType=PKCS12")
+
+		data, err := os.ReadFile(filepath.Join(dir, trustStoreFile))
+		assert.NoError(t, err)
+
+		certs, err := pkcs12.DecodeTrustStore(data, javaOption(options, "javax.net.ssl.trustStorePassword"))
+		assert.NoError(t, err)
+		assert.Len(t, certs, 1)
+		assert.Equal(t, "Internal CA", certs[0].Subject.CommonName)
+	})
+	t.Run("Given a CA bundle and a JVM", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("JAVA_HOME", home)
+		writeJavaTrustStore(t, filepath.Join(home, "lib", "security", "cacerts"), pkcs12TrustStore(t, "Public CA"))
+		dir := t.TempDir()
+		config := Config{CABundle: ca}
+
+		options, err := config.JavaOptions(dir)
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, trustStoreFile))
+		assert.NoError(t, err)
+
+		// The CA bundle is trusted in addition to the default trust store of the JVM, as for the API client
+		certs, err := pkcs12.DecodeTrustStore(data, javaOption(options, "javax.net.ssl.trustStorePassword"))
+		assert.NoError(t, err)
+		assert.Len(t, certs, 2)
+		assert.Equal(t, "Internal CA", certs[0].Subject.CommonName)
+		assert.Equal(t, "Public CA", certs[1].Subject.CommonName)
+	})
+	t.Run("Given a client certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		config := Config{ClientCertificate: cert, ClientKey: key}
+
+		options, err := config.JavaOptions(dir)
+
+		assert.NoError(t, err)
+		assert.Contains(t, options, "-Djavax.net.ssl.keyStore="+filepath.Join(dir, keyStoreFile))
+		assert.Contains(t, options, "-Djavax.net.ssl.keyStoreType=PKCS12")
+
+		data, err := os.ReadFile(filepath.Join(dir, keyStoreFile))
+		assert.NoError(t, err)
+
+		_, leaf, _, err := pkcs12.DecodeChain(data, javaOption(options, "javax.net.ssl.keyStorePassword"))
+		assert.NoError(t, err)
+		assert.Equal(t, "terraform", leaf.Subject.CommonName)
+	})
+	t.Run("Given a proxy", func(t *testing.T) {
+		config := Config{ProxyURL: proxyURL, NoProxy: "localhost,.example.com"}
+
+		options, err := config.JavaOptions(t.TempDir())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"-Dhttp.proxyHost=proxy.internal",
+			"-Dhttp.proxyPort=3128",
+			"-Dhttps.proxyHost=proxy.internal",
+			"-Dhttps.proxyPort=3128",
+			"-Dhttp.nonProxyHosts=localhost|*.localhost|example.com|*.example.com",
+		}, options)
+	})
+}
+
+func TestJavaNonProxyHosts(t *testing.T) {
+	tests := []struct {
+		name     string
+		noProxy  string
+		expected string
+	}{
+		{"Given no hosts", "", ""},
+		{"Given all hosts", "example.com,*", "*"},
+		{"Given domains", "example.com, .example.org,*.example.net", "example.com|*.example.com|example.org|*.example.org|example.net|*.example.net"},
+		{"Given a host with a port", "example.com:8080", "example.com|*.example.com"},
+		{"Given a CIDR range", "10.0.0.0/8,example.com", "example.com|*.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, javaNonProxyHosts(tt.noProxy))
+		})
+	}
+}
+
+func TestJavaProperty(t *testing.T) {
+	assert.Equal(t, "-Dhttp.proxyHost=proxy", javaProperty("http.proxyHost", "proxy"))
+	assert.Equal(t, `"-Djavax.net.ssl.trustStore=C:\Program Files\truststore.p12"`, javaProperty("javax.net.ssl.trustStore", `C:\Program Files\truststore.p12`))
+}
+
+// javaOption returns the value of a JVM system property from a list of options
+func javaOption(options []string, name string) string {
+	for _, option := range options {
+		if v, ok := strings.CutPrefix(option, "-D"+name+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
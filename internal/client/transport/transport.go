@@ -0,0 +1,78 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"golang.org/x/net/http/httpproxy"
+	"net/http"
+	"net/url"
+)
+
+// Config holds the TLS and proxy settings shared by the API client and the Structurizr CLI
+type Config struct {
+	// TLSInsecure disables the verification of the certificate of the remote server
+	TLSInsecure bool
+	// CABundle contains PEM encoded certificates trusted in addition to the system ones
+	CABundle []byte
+	// ClientCertificate contains the PEM encoded certificate chain presented to the remote server
+	ClientCertificate []byte
+	// ClientKey contains the PEM encoded private key of the client certificate
+	ClientKey []byte
+	// ProxyURL overrides the proxy configured with the HTTP_PROXY and HTTPS_PROXY environment variables
+	ProxyURL *url.URL
+	// NoProxy overrides the hosts excluded from the proxy configured with the NO_PROXY environment variable
+	NoProxy string
+}
+
+// TLSConfig returns the TLS configuration trusting the CA bundle and presenting the client certificate
+func (c Config) TLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: c.TLSInsecure}
+
+	if len(c.CABundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(c.CABundle) {
+			return nil, errors.New("the CA bundle does not contain any PEM encoded certificate")
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	if len(c.ClientCertificate) > 0 || len(c.ClientKey) > 0 {
+		cert, err := tls.X509KeyPair(c.ClientCertificate, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// Proxy returns the function selecting the proxy of a request, the environment variables are used unless overridden
+func (c Config) Proxy() func(*http.Request) (*url.URL, error) {
+	if c.ProxyURL == nil && c.NoProxy == "" {
+		return http.ProxyFromEnvironment
+	}
+
+	cfg := httpproxy.FromEnvironment()
+	if c.ProxyURL != nil {
+		cfg.HTTPProxy = c.ProxyURL.String()
+		cfg.HTTPSProxy = c.ProxyURL.String()
+	}
+	if c.NoProxy != "" {
+		cfg.NoProxy = c.NoProxy
+	}
+
+	proxy := cfg.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
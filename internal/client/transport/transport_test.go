@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// newCertificate generates a self-signed PEM encoded certificate and its private key
+func newCertificate(t *testing.T, commonName string) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestTLSConfig(t *testing.T) {
+	ca, _ := newCertificate(t, "Internal CA")
+	cert, key := newCertificate(t, "terraform")
+	_, otherKey := newCertificate(t, "other")
+
+	t.Run("Given disabled TLS verification", func(t *testing.T) {
+		cfg, err := Config{TLSInsecure: true}.TLSConfig()
+
+		assert.NoError(t, err)
+		assert.True(t, cfg.InsecureSkipVerify)
+		assert.Nil(t, cfg.RootCAs)
+		assert.Empty(t, cfg.Certificates)
+	})
+	t.Run("Given a CA bundle", func(t *testing.T) {
+		cfg, err := Config{CABundle: ca}.TLSConfig()
+
+		assert.NoError(t, err)
+		assert.False(t, cfg.InsecureSkipVerify)
+		assert.NotNil(t, cfg.RootCAs)
+	})
+	t.Run("Given an invalid CA bundle", func(t *testing.T) {
+		_, err := Config{CABundle: []byte("not a certificate")}.TLSConfig()
+
+		assert.ErrorContains(t, err, "does not contain any PEM encoded certificate")
+	})
+	t.Run("Given a client certificate", func(t *testing.T) {
+		cfg, err := Config{ClientCertificate: cert, ClientKey: key}.TLSConfig()
+
+		assert.NoError(t, err)
+		assert.Len(t, cfg.Certificates, 1)
+	})
+	t.Run("Given a client certificate with another key", func(t *testing.T) {
+		_, err := Config{ClientCertificate: cert, ClientKey: otherKey}.TLSConfig()
+
+		assert.ErrorContains(t, err, "invalid client certificate or key")
+	})
+}
+
+func TestProxy(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.internal:3128")
+
+	tests := []struct {
+		name     string
+		config   Config
+		target   string
+		expected string
+	}{
+		{"Given a proxy", Config{ProxyURL: proxyURL}, "https://structurizr.example.com", "http://proxy.internal:3128"},
+		{"Given a host excluded from the proxy", Config{ProxyURL: proxyURL, NoProxy: "example.com"}, "https://structurizr.example.com", ""},
+		{"Given another host excluded from the proxy", Config{ProxyURL: proxyURL, NoProxy: "example.org"}, "https://structurizr.example.com", "http://proxy.internal:3128"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.target, nil)
+
+			actual, err := tt.config.Proxy()(req)
+
+			assert.NoError(t, err)
+			if tt.expected == "" {
+				assert.Nil(t, actual)
+			} else {
+				assert.Equal(t, tt.expected, actual.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"io"
+)
+
+const (
+	// DefaultKeySize is the AES key size (in bits) used by Structurizr
+	DefaultKeySize = 128
+	// DefaultIterationCount is the number of PBKDF2 iterations used by Structurizr
+	DefaultIterationCount = 1000
+	// strategyType is the type of the only encryption strategy supported by Structurizr
+	strategyType = "aes"
+	// strategyLocation indicates the workspace has been encrypted before being sent to the server
+	strategyLocation = "Client"
+	// saltSize is the size (in bytes) of the random salt used to derive the key
+	saltSize = 16
+)
+
+// ErrInvalidPassphrase is returned when a ciphertext cannot be decrypted with the given passphrase
+var ErrInvalidPassphrase = errors.New("unable to decrypt the workspace, please check the passphrase")
+
+// AESStrategy represents the client-side encryption settings stored alongside an encrypted workspace.
+// It mirrors the AesEncryptionStrategy used by Structurizr, which derives an AES key from the passphrase
+// with PBKDF2 (HMAC-SHA1) and encrypts the workspace with AES/CBC/PKCS5Padding.
+type AESStrategy struct {
+	Type           string `json:"type"`
+	Location       string `json:"location"`
+	KeySize        int    `json:"keySize"`
+	IterationCount int    `json:"iterationCount"`
+	Salt           string `json:"salt"`
+	IV             string `json:"iv"`
+}
+
+// NewAESStrategy creates a strategy with a random salt and initialisation vector read from the given source,
+// which is usually crypto/rand.Reader.
+func NewAESStrategy(keySize int, iterationCount int, random io.Reader) (*AESStrategy, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(random, salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(random, iv); err != nil {
+		return nil, fmt.Errorf("failed to generate initialisation vector: %w", err)
+	}
+
+	return &AESStrategy{
+		Type:           strategyType,
+		Location:       strategyLocation,
+		KeySize:        keySize,
+		IterationCount: iterationCount,
+		Salt:           hex.EncodeToString(salt),
+		IV:             hex.EncodeToString(iv),
+	}, nil
+}
+
+// Encrypt encrypts the plaintext using the strategy and the given passphrase into a base64 encoded ciphertext
+func (s *AESStrategy) Encrypt(plaintext []byte, passphrase string) (string, error) {
+	block, iv, err := s.cipher(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	data := pad(plaintext)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Decrypt decrypts a base64 encoded ciphertext using the strategy and the given passphrase
+func (s *AESStrategy) Decrypt(ciphertext string, passphrase string) ([]byte, error) {
+	block, iv, err := s.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("ciphertext is not a multiple of the block size (%d)", aes.BlockSize)
+	}
+
+	plaintext := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, data)
+
+	return unpad(plaintext)
+}
+
+// cipher derives the AES key from the passphrase and decodes the initialisation vector
+func (s *AESStrategy) cipher(passphrase string) (cipher.Block, []byte, error) {
+	if passphrase == "" {
+		return nil, nil, errors.New("a passphrase is required")
+	}
+
+	if s.KeySize != 128 && s.KeySize != 192 && s.KeySize != 256 {
+		return nil, nil, fmt.Errorf("unsupported key size: %d", s.KeySize)
+	}
+
+	if s.IterationCount <= 0 {
+		return nil, nil, fmt.Errorf("unsupported iteration count: %d", s.IterationCount)
+	}
+
+	salt, err := hex.DecodeString(s.Salt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode salt: %w", err)
+	}
+
+	iv, err := hex.DecodeString(s.IV)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode initialisation vector: %w", err)
+	}
+
+	if len(iv) != aes.BlockSize {
+		return nil, nil, fmt.Errorf("initialisation vector must be %d bytes long", aes.BlockSize)
+	}
+
+	key := pbkdf2.Key([]byte(passphrase), salt, s.IterationCount, s.KeySize/8, sha1.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return block, iv, nil
+}
+
+// pad adds the PKCS#5/PKCS#7 padding to fill the last block
+func pad(data []byte) []byte {
+	n := aes.BlockSize - len(data)%aes.BlockSize
+	return append(bytes.Clone(data), bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+// unpad removes the PKCS#5/PKCS#7 padding, a malformed padding is the usual symptom of a wrong passphrase
+func unpad(data []byte) ([]byte, error) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > aes.BlockSize || n > len(data) {
+		return nil, ErrInvalidPassphrase
+	}
+
+	if !bytes.Equal(data[len(data)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, ErrInvalidPassphrase
+	}
+
+	return data[:len(data)-n], nil
+}
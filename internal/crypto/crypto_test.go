@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const (
+	testCiphertext = "37Q2zFesbKH6PzIwrn/6nvL+lDUxWCRQLgkDiJFRW1J+Ub2+S1lsfUxoxBt8ou99OVM9Rm3Q/1iXKHmZbnr8Gw=="
+	testPlaintext  = `{"id":1,"name":"Encrypted Workspace","model":{}}`
+)
+
+func testStrategy() *AESStrategy {
+	return &AESStrategy{
+		KeySize:        128,
+		IterationCount: 1000,
+		Salt:           "8f2cb3a0e34b5a3c1b1e0d3c5e6f7a81",
+		IV:             "00112233445566778899aabbccddeeff",
+	}
+}
+
+func TestAESStrategy_Decrypt(t *testing.T) {
+	t.Run("Given the right passphrase", func(t *testing.T) {
+		plaintext, err := testStrategy().Decrypt(testCiphertext, "structurizr")
+
+		assert.NoError(t, err)
+		assert.Equal(t, testPlaintext, string(plaintext))
+	})
+	t.Run("Given a wrong passphrase", func(t *testing.T) {
+		_, err := testStrategy().Decrypt(testCiphertext, "wrong")
+
+		assert.ErrorIs(t, err, ErrInvalidPassphrase)
+	})
+	t.Run("Given no passphrase", func(t *testing.T) {
+		_, err := testStrategy().Decrypt(testCiphertext, "")
+
+		assert.ErrorContains(t, err, "a passphrase is required")
+	})
+	t.Run("Given an unsupported key size", func(t *testing.T) {
+		s := testStrategy()
+		s.KeySize = 64
+
+		_, err := s.Decrypt(testCiphertext, "structurizr")
+
+		assert.ErrorContains(t, err, "unsupported key size")
+	})
+	t.Run("Given a malformed ciphertext", func(t *testing.T) {
+		_, err := testStrategy().Decrypt("bm90IGEgYmxvY2s=", "structurizr")
+
+		assert.ErrorContains(t, err, "block size")
+	})
+}
+
+func TestNewAESStrategy(t *testing.T) {
+	t.Run("Given enough random bytes", func(t *testing.T) {
+		random := bytes.NewReader(bytes.Repeat([]byte{0xab}, 32))
+
+		s, err := NewAESStrategy(DefaultKeySize, DefaultIterationCount, random)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &AESStrategy{
+			Type:           "aes",
+			Location:       "Client",
+			KeySize:        128,
+			IterationCount: 1000,
+			Salt:           "abababababababababababababababab",
+			IV:             "abababababababababababababababab",
+		}, s)
+	})
+	t.Run("Given not enough random bytes", func(t *testing.T) {
+		_, err := NewAESStrategy(DefaultKeySize, DefaultIterationCount, bytes.NewReader([]byte{0xab}))
+
+		assert.ErrorContains(t, err, "failed to generate salt")
+	})
+}
+
+func TestAESStrategy_Encrypt(t *testing.T) {
+	t.Run("Given a known strategy", func(t *testing.T) {
+		ciphertext, err := testStrategy().Encrypt([]byte(testPlaintext), "structurizr")
+
+		assert.NoError(t, err)
+		assert.Equal(t, testCiphertext, ciphertext)
+	})
+	t.Run("Given a plaintext filling whole blocks", func(t *testing.T) {
+		plaintext := bytes.Repeat([]byte("a"), 32)
+
+		ciphertext, err := testStrategy().Encrypt(plaintext, "structurizr")
+		assert.NoError(t, err)
+
+		decrypted, err := testStrategy().Decrypt(ciphertext, "structurizr")
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+	})
+}
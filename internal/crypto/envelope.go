@@ -0,0 +1,65 @@
+package crypto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Envelope is the JSON document of a workspace encrypted on the client-side. The workspace metadata and its
+// configuration (e.g. users, visibility) are kept in clear so Structurizr can list the workspace and enforce its
+// access, whereas its content is only available as ciphertext.
+type Envelope struct {
+	ID                 int64           `json:"id"`
+	Name               string          `json:"name"`
+	Description        string          `json:"description"`
+	Revision           int64           `json:"revision,omitempty"`
+	LastModifiedDate   string          `json:"lastModifiedDate,omitempty"`
+	LastModifiedUser   string          `json:"lastModifiedUser,omitempty"`
+	LastModifiedAgent  string          `json:"lastModifiedAgent,omitempty"`
+	Configuration      json.RawMessage `json:"configuration,omitempty"`
+	Ciphertext         string          `json:"ciphertext"`
+	EncryptionStrategy *AESStrategy    `json:"encryptionStrategy"`
+}
+
+// Seal encrypts a JSON workspace with the strategy and wraps it into an envelope
+func Seal(workspace []byte, passphrase string, strategy *AESStrategy) ([]byte, error) {
+	envelope := new(Envelope)
+	if err := json.Unmarshal(workspace, envelope); err != nil {
+		return nil, fmt.Errorf("failed to read workspace metadata: %w", err)
+	}
+
+	ciphertext, err := strategy.Encrypt(workspace, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.Ciphertext = ciphertext
+	envelope.EncryptionStrategy = strategy
+
+	return json.Marshal(envelope)
+}
+
+// Open decrypts the JSON workspace wrapped into an envelope
+func Open(data []byte, passphrase string) ([]byte, error) {
+	envelope := new(Envelope)
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return nil, fmt.Errorf("failed to read encrypted workspace: %w", err)
+	}
+
+	if envelope.EncryptionStrategy == nil || envelope.Ciphertext == "" {
+		return nil, errors.New("the workspace is not encrypted")
+	}
+
+	workspace, err := envelope.EncryptionStrategy.Decrypt(envelope.Ciphertext, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	// A wrong passphrase can still produce a valid padding, the workspace itself is then garbage
+	if !json.Valid(workspace) {
+		return nil, ErrInvalidPassphrase
+	}
+
+	return workspace, nil
+}
@@ -0,0 +1,81 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSeal(t *testing.T) {
+	workspace := []byte(`{"id":1,"name":"Encrypted Workspace","description":"Secret","model":{}}`)
+	strategy, err := NewAESStrategy(DefaultKeySize, DefaultIterationCount, bytes.NewReader(bytes.Repeat([]byte{0xab}, 32)))
+	assert.NoError(t, err)
+
+	data, err := Seal(workspace, "structurizr", strategy)
+	assert.NoError(t, err)
+
+	envelope := new(Envelope)
+	assert.NoError(t, json.Unmarshal(data, envelope))
+	assert.Equal(t, int64(1), envelope.ID)
+	assert.Equal(t, "Encrypted Workspace", envelope.Name)
+	assert.Equal(t, "Secret", envelope.Description)
+	assert.Equal(t, strategy, envelope.EncryptionStrategy)
+	assert.NotContains(t, string(data), `"model"`)
+
+	opened, err := Open(data, "structurizr")
+	assert.NoError(t, err)
+	assert.Equal(t, workspace, opened)
+}
+
+func TestSeal_Configuration(t *testing.T) {
+	configuration := `{"users":[{"username":"admin","role":"ReadWrite"}],"visibility":"Private"}`
+	workspace := []byte(`{"id":1,"name":"Encrypted Workspace","configuration":` + configuration + `,"model":{}}`)
+	strategy, err := NewAESStrategy(DefaultKeySize, DefaultIterationCount, bytes.NewReader(bytes.Repeat([]byte{0xab}, 32)))
+	assert.NoError(t, err)
+
+	data, err := Seal(workspace, "structurizr", strategy)
+	assert.NoError(t, err)
+
+	// The configuration is kept in clear next to the ciphertext, as well as within it
+	envelope := new(Envelope)
+	assert.NoError(t, json.Unmarshal(data, envelope))
+	assert.JSONEq(t, configuration, string(envelope.Configuration))
+
+	opened, err := Open(data, "structurizr")
+	assert.NoError(t, err)
+	assert.Equal(t, workspace, opened)
+}
+
+func TestOpen(t *testing.T) {
+	envelope := `{
+  "id": 1,
+  "name": "Encrypted Workspace",
+  "ciphertext": "` + testCiphertext + `",
+  "encryptionStrategy": {
+    "type": "aes",
+    "location": "Client",
+    "keySize": 128,
+    "iterationCount": 1000,
+    "salt": "8f2cb3a0e34b5a3c1b1e0d3c5e6f7a81",
+    "iv": "00112233445566778899aabbccddeeff"
+  }
+}`
+
+	t.Run("Given the right passphrase", func(t *testing.T) {
+		workspace, err := Open([]byte(envelope), "structurizr")
+
+		assert.NoError(t, err)
+		assert.Equal(t, testPlaintext, string(workspace))
+	})
+	t.Run("Given a wrong passphrase", func(t *testing.T) {
+		_, err := Open([]byte(envelope), "wrong")
+
+		assert.ErrorIs(t, err, ErrInvalidPassphrase)
+	})
+	t.Run("Given a workspace which is not encrypted", func(t *testing.T) {
+		_, err := Open([]byte(`{"id":1,"model":{}}`), "structurizr")
+
+		assert.ErrorContains(t, err, "not encrypted")
+	})
+}
@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// workspaceCredentials returns the configured workspace API key and secret, otherwise they are looked up
+// from the list of workspaces using the admin API key of the provider.
+func workspaceCredentials(
+	ctx context.Context,
+	m *client.Manager,
+	id int64,
+	key types.String,
+	secret types.String,
+) (string, string, error) {
+	if key.ValueString() != "" && secret.ValueString() != "" {
+		return key.ValueString(), secret.ValueString(), nil
+	}
+
+	res, err := m.GetWorkspaces(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list workspaces with error: %w", err)
+	}
+
+	workspace := res.FindByID(id)
+	if workspace == nil {
+		return "", "", fmt.Errorf("workspace (id: %d) not found on remote server", id)
+	}
+
+	return workspace.APIKey, workspace.APISecret, nil
+}
+
+// credentialProcessTimeout bounds the execution of the credential process
+const credentialProcessTimeout = 1 * time.Minute
+
+// adminAPIKeySource is a source of the admin API key of the provider, its value is either the key itself
+// or resolved into the key, such as a file or a command
+type adminAPIKeySource struct {
+	name    string
+	env     string
+	value   types.String
+	resolve func(ctx context.Context, v string) (string, error)
+}
+
+// adminAPIKeyConfig returns the admin API key of the provider from the first configured source,
+// an empty string is returned when none is configured
+func adminAPIKeyConfig(ctx context.Context, diags *diag.Diagnostics, config StructurizrProviderModel) string {
+	// The sources in order of precedence, the configuration values take precedence over the environment variables
+	sources := []adminAPIKeySource{
+		{"admin_api_key", "STRUCTURIZR_ADMIN_API_KEY", config.AdminAPIKey, nil},
+		{"admin_api_key_file", "STRUCTURIZR_ADMIN_API_KEY_FILE", config.AdminAPIKeyFile, readCredentialFile},
+		{"credential_process", "STRUCTURIZR_CREDENTIAL_PROCESS", config.CredentialProcess, runCredentialProcess},
+	}
+
+	for _, fromEnv := range []bool{false, true} {
+		for _, s := range sources {
+			v, origin := s.value.ValueString(), s.name
+			if fromEnv {
+				v, origin = os.Getenv(s.env), s.env+" environment variable"
+			}
+
+			if v == "" {
+				continue
+			}
+
+			if s.resolve == nil {
+				return v
+			}
+
+			tflog.Debug(ctx, fmt.Sprintf("Reading the Structurizr admin API key from the %s", origin))
+
+			key, err := s.resolve(ctx, v)
+			if err == nil && key == "" {
+				err = errors.New("the admin API key is empty")
+			}
+
+			if err != nil {
+				summary := "Unable to read the Structurizr Admin API Key"
+				detail := fmt.Sprintf("The admin API key could not be read from the %s.\n\nError: %s", origin, err)
+
+				// An environment variable is not an attribute of the configuration to point at
+				if fromEnv {
+					diags.AddError(summary, detail)
+				} else {
+					diags.AddAttributeError(path.Root(s.name), summary, detail)
+				}
+			}
+
+			return key
+		}
+	}
+
+	return ""
+}
+
+// readCredentialFile returns the content of a file holding a secret, such as one mounted by Vault Agent,
+// without its surrounding whitespaces
+func readCredentialFile(_ context.Context, name string) (string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// runCredentialProcess runs a command, which is not interpreted by a shell, and returns its standard output
+// without its surrounding whitespaces
+func runCredentialProcess(ctx context.Context, command string) (string, error) {
+	args, err := splitCommand(command)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err = cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %w\n%s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// splitCommand splits a command line into its arguments separated by whitespaces,
+// which are kept within single or double quotes
+func splitCommand(command string) ([]string, error) {
+	var (
+		args    []string
+		arg     strings.Builder
+		inArg   bool
+		inQuote rune
+	)
+
+	for _, r := range command {
+		switch {
+		case inQuote != 0 && r == inQuote:
+			inQuote = 0
+		case inQuote != 0:
+			arg.WriteRune(r)
+		case r == '"' || r == '\'':
+			inQuote, inArg = r, true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inQuote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command: %s", command)
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+
+	return args, nil
+}
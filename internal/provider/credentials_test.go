@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAdminAPIKeyConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "admin_api_key")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		config   StructurizrProviderModel
+		env      map[string]string
+		expected string
+		wantErr  bool
+	}{
+		{
+			"Given an admin API key",
+			StructurizrProviderModel{AdminAPIKey: types.StringValue("from-config")},
+			map[string]string{"STRUCTURIZR_ADMIN_API_KEY_FILE": file},
+			"from-config",
+			false,
+		},
+		{
+			"Given an admin API key file",
+			StructurizrProviderModel{AdminAPIKeyFile: types.StringValue(file)},
+			map[string]string{"STRUCTURIZR_ADMIN_API_KEY": "from-env"},
+			"from-file",
+			false,
+		},
+		{
+			"Given a credential process",
+			StructurizrProviderModel{CredentialProcess: types.StringValue(`sh -c "echo ' from-process '"`)},
+			nil,
+			"from-process",
+			false,
+		},
+		{
+			"Given environment variables only",
+			StructurizrProviderModel{},
+			map[string]string{"STRUCTURIZR_ADMIN_API_KEY_FILE": file, "STRUCTURIZR_CREDENTIAL_PROCESS": "echo from-process"},
+			"from-file",
+			false,
+		},
+		{
+			"Given no admin API key",
+			StructurizrProviderModel{},
+			nil,
+			"",
+			false,
+		},
+		{
+			"Given a missing admin API key file",
+			StructurizrProviderModel{AdminAPIKeyFile: types.StringValue(filepath.Join(t.TempDir(), "missing"))},
+			nil,
+			"",
+			true,
+		},
+		{
+			"Given a failing credential process",
+			StructurizrProviderModel{CredentialProcess: types.StringValue("false")},
+			nil,
+			"",
+			true,
+		},
+		{
+			"Given a credential process printing nothing",
+			StructurizrProviderModel{CredentialProcess: types.StringValue("true")},
+			nil,
+			"",
+			true,
+		},
+		{
+			"Given a missing admin API key file from the environment",
+			StructurizrProviderModel{},
+			map[string]string{"STRUCTURIZR_ADMIN_API_KEY_FILE": filepath.Join(t.TempDir(), "missing")},
+			"",
+			true,
+		},
+		{
+			"Given a failing credential process from the environment",
+			StructurizrProviderModel{},
+			map[string]string{"STRUCTURIZR_CREDENTIAL_PROCESS": "false"},
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, env := range []string{"STRUCTURIZR_ADMIN_API_KEY", "STRUCTURIZR_ADMIN_API_KEY_FILE", "STRUCTURIZR_CREDENTIAL_PROCESS"} {
+				t.Setenv(env, tt.env[env])
+			}
+
+			var diags diag.Diagnostics
+			actual := adminAPIKeyConfig(context.Background(), &diags, tt.config)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.wantErr, diags.HasError())
+
+			// Only the failures of the configuration values point at their attribute
+			if tt.wantErr {
+				_, withPath := diags.Errors()[0].(diag.DiagnosticWithPath)
+				assert.Equal(t, tt.env == nil, withPath)
+			}
+		})
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected []string
+		wantErr  bool
+	}{
+		{"vault kv get -field=api_key secret/structurizr", []string{"vault", "kv", "get", "-field=api_key", "secret/structurizr"}, false},
+		{`"/opt/my tools/get-key"  --name 'admin key'`, []string{"/opt/my tools/get-key", "--name", "admin key"}, false},
+		{`get-key ""`, []string{"get-key", ""}, false},
+		{`get-key "unterminated`, nil, true},
+		{"   ", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			actual, err := splitCommand(tt.command)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
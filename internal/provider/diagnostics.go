@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+)
+
+// errorHints maps the errors returned by the Structurizr API, or the timeouts of operations, to a hint on how to resolve them
+var errorHints = []struct {
+	err  error
+	hint string
+}{
+	{
+		api.ErrMissingAdminAPIKey,
+		"Set the admin_api_key of the provider or the STRUCTURIZR_ADMIN_API_KEY environment variable. " +
+			"Without admin API, such as on the cloud service, manage the content of existing workspaces " +
+			"with the structurizr_workspace_content resource instead.",
+	},
+	{
+		api.ErrMergeUnsupported,
+		"Set the push_client of the provider to \"cli\", or set preserve_layout to false on the resource or the provider.",
+	},
+	{
+		model.APIErrUnauthorized,
+		"Check the admin API key of the provider, or the API key and secret of the Workspace, " +
+			"and that the clock of this machine is in sync as requests are signed with a timestamp.",
+	},
+	{
+		model.APIErrForbidden,
+		"The API key is not allowed to perform this operation. Check that the admin API key is enabled " +
+			"on the remote server (structurizr.apiKey) and that this machine is allowed to reach the admin API.",
+	},
+	{
+		model.APIErrNotFound,
+		"The Workspace does not exist on the remote server, it may have been deleted outside of Terraform.",
+	},
+	{
+		model.APIErrConflict,
+		"The Workspace is locked by another user or agent (e.g. the Structurizr UI). " +
+			"Retry once the Workspace has been unlocked.",
+	},
+	{
+		model.APIErrRateLimited,
+		"The remote server is rate limiting requests. Increase max_retries or max_retry_wait in the provider " +
+			"configuration, or reduce the parallelism of Terraform.",
+	},
+	{
+		model.APIErrUnexpectedContent,
+		"The remote server did not answer with JSON. A reverse proxy or an SSO gateway may be intercepting " +
+			"the requests, check the host of the provider points straight to Structurizr.",
+	},
+	{
+		context.DeadlineExceeded,
+		"The operation did not complete in time. Increase the timeouts of the resource, or the api_timeout " +
+			"and cli_timeout of the provider when a single request or Structurizr CLI run is slow.",
+	},
+	{
+		model.APIErrSystemUnavailable,
+		"The remote server is unavailable. Check its health, then retry.",
+	},
+}
+
+// errorDetail formats an error for a diagnostic detail, followed by a hint on how to resolve it when known
+func errorDetail(err error) string {
+	for _, h := range errorHints {
+		if errors.Is(err, h.err) {
+			return fmt.Sprintf("%s\n\n%s", err, h.hint)
+		}
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestErrorDetail(t *testing.T) {
+	t.Run("Given a known API error", func(t *testing.T) {
+		err := fmt.Errorf("failed to list workspaces with error: %w", &model.APIErrorResponse{Err: model.APIErrForbidden})
+
+		actual := errorDetail(err)
+
+		assert.True(t, strings.HasPrefix(actual, err.Error()))
+		assert.Contains(t, actual, "not allowed to perform this operation")
+	})
+	t.Run("Given a missing admin API key", func(t *testing.T) {
+		err := fmt.Errorf("failed to create workspace with error: %w", api.ErrMissingAdminAPIKey)
+
+		assert.Contains(t, errorDetail(err), "structurizr_workspace_content")
+	})
+	t.Run("Given a layout merge with the API push client", func(t *testing.T) {
+		assert.Contains(t, errorDetail(api.ErrMergeUnsupported), "preserve_layout to false")
+	})
+	t.Run("Given a timeout", func(t *testing.T) {
+		err := fmt.Errorf("error running Structurizr CLI: %w", context.DeadlineExceeded)
+
+		assert.Contains(t, errorDetail(err), "Increase the timeouts of the resource")
+	})
+	t.Run("Given an unknown error", func(t *testing.T) {
+		err := errors.New("boom")
+
+		assert.Equal(t, "boom", errorDetail(err))
+	})
+}
@@ -2,22 +2,41 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"github.com/fstaoe/terraform-provider-structurizr/internal/client"
 	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api"
 	"github.com/fstaoe/terraform-provider-structurizr/internal/client/cli"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/retry"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/transport"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 )
 
 // Ensure Structurizr satisfies various provider interfaces.
-var _ provider.Provider = (*Structurizr)(nil)
+var (
+	_ provider.Provider              = (*Structurizr)(nil)
+	_ provider.ProviderWithFunctions = (*Structurizr)(nil)
+)
+
+const (
+	// pushClientCLI pushes workspaces through the embedded Structurizr CLI which requires a JVM
+	pushClientCLI = "cli"
+	// pushClientAPI pushes JSON workspaces straight to the Structurizr workspace API
+	pushClientAPI = "api"
+)
 
 func New(version string) func() provider.Provider {
 	return func() provider.Provider { return &Structurizr{version: version} }
@@ -33,9 +52,29 @@ type Structurizr struct {
 
 // StructurizrProviderModel describes the provider data model.
 type StructurizrProviderModel struct {
-	Host        types.String `tfsdk:"host"`
-	AdminAPIKey types.String `tfsdk:"admin_api_key"`
-	TLSInsecure types.Bool   `tfsdk:"tls_insecure"`
+	Host                  types.String `tfsdk:"host"`
+	AdminAPIKey           types.String `tfsdk:"admin_api_key"`
+	AdminAPIKeyFile       types.String `tfsdk:"admin_api_key_file"`
+	CredentialProcess     types.String `tfsdk:"credential_process"`
+	TLSInsecure           types.Bool   `tfsdk:"tls_insecure"`
+	PushClient            types.String `tfsdk:"push_client"`
+	MaxRetries            types.Int64  `tfsdk:"max_retries"`
+	MinRetryWait          types.String `tfsdk:"min_retry_wait"`
+	MaxRetryWait          types.String `tfsdk:"max_retry_wait"`
+	CABundle              types.String `tfsdk:"ca_bundle"`
+	ClientCert            types.String `tfsdk:"client_certificate"`
+	ClientKey             types.String `tfsdk:"client_key"`
+	ProxyURL              types.String `tfsdk:"proxy_url"`
+	NoProxy               types.String `tfsdk:"no_proxy"`
+	APITimeout            types.String `tfsdk:"api_timeout"`
+	CLITimeout            types.String `tfsdk:"cli_timeout"`
+	CLIDir                types.String `tfsdk:"cli_dir"`
+	MaxParallelOperations types.Int64  `tfsdk:"max_parallel_operations"`
+	PreserveLayout        types.Bool   `tfsdk:"preserve_layout"`
+	Archive               types.Bool   `tfsdk:"archive"`
+	ArchiveDir            types.String `tfsdk:"archive_dir"`
+	ArchiveRetention      types.Int64  `tfsdk:"archive_retention"`
+	ValidateSources       types.Bool   `tfsdk:"validate_sources"`
 }
 
 // Metadata returns the provider type name and version. It can be used to register other type of information
@@ -49,18 +88,162 @@ func (p *Structurizr) Schema(_ context.Context, _ provider.SchemaRequest, resp *
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				Required:    true,
-				Description: "A fully qualified hostname (e.g. https://my.structurizr.instance)",
+				Required: true,
+				Description: fmt.Sprintf(
+					"A fully qualified hostname (e.g. https://my.structurizr.instance), or `https://%s` for the cloud service",
+					api.CloudServiceHost,
+				),
 			},
 			"admin_api_key": schema.StringAttribute{
-				Sensitive:   true,
-				Optional:    true,
-				Description: "An API Key to be authorised against Structurizr API",
+				Sensitive: true,
+				Optional:  true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("admin_api_key_file"), path.MatchRoot("credential_process")),
+				},
+				Description: "The admin API key used to create, list and delete workspaces on Structurizr on-premises. " +
+					"It can be omitted when only the content of existing workspaces is managed with " +
+					"`structurizr_workspace_content`, such as on the cloud service which has no admin API. " +
+					"The first admin API key found is used, in order: `admin_api_key`, `admin_api_key_file`, " +
+					"`credential_process`, then the `STRUCTURIZR_ADMIN_API_KEY`, `STRUCTURIZR_ADMIN_API_KEY_FILE` and " +
+					"`STRUCTURIZR_CREDENTIAL_PROCESS` environment variables.",
+			},
+			"admin_api_key_file": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("credential_process")),
+				},
+				Description: "The path to a file containing the admin API key, such as a secret mounted by Vault Agent. " +
+					"Surrounding whitespaces are ignored.",
+			},
+			"credential_process": schema.StringAttribute{
+				Optional: true,
+				Description: "A command printing the admin API key on its standard output, similar to the AWS " +
+					"`credential_process` (e.g. `vault kv get -field=api_key secret/structurizr`). The command is not " +
+					"run in a shell, arguments containing whitespaces must be quoted.",
 			},
 			"tls_insecure": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Disable TLS verification checks for self-hosted structurizr with self-signed certificates",
 			},
+			"push_client": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pushClientCLI, pushClientAPI),
+				},
+				Description: "The client used to push workspace sources: `cli` (default) runs the embedded Structurizr CLI " +
+					"and requires Java, `api` pushes JSON sources straight to the workspace API without Java, " +
+					"encrypting them on the client-side when a passphrase is set.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:   true,
+				Validators: []validator.Int64{int64validator.AtLeast(0)},
+				Description: fmt.Sprintf(
+					"The maximum number of retries of requests and Structurizr CLI pushes failing with transient errors "+
+						"(e.g. connection errors, 5xx or 429 responses). Defaults to `%d`.",
+					retry.DefaultMaxRetries,
+				),
+			},
+			"max_parallel_operations": schema.Int64Attribute{
+				Optional:   true,
+				Validators: []validator.Int64{int64validator.AtLeast(1)},
+				Description: fmt.Sprintf(
+					"The maximum number of operations (e.g. requests, Structurizr CLI pushes) run in parallel against "+
+						"the remote server, whatever the parallelism of Terraform. Operations modifying the same "+
+						"Workspace are always run one at a time. Defaults to `%d`.",
+					client.DefaultMaxParallelOperations,
+				),
+			},
+			"min_retry_wait": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"The minimum wait before retrying, doubled on every attempt (e.g. `500ms`, `2s`). Defaults to `%s`.",
+					retry.DefaultMinWait,
+				),
+			},
+			"max_retry_wait": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"The maximum wait between two retries, including waits requested with a `Retry-After` header. Defaults to `%s`.",
+					retry.DefaultMaxWait,
+				),
+			},
+			"ca_bundle": schema.StringAttribute{
+				Optional: true,
+				Description: "PEM encoded CA certificates, or the path to a file containing them, trusted in addition to " +
+					"the system ones (e.g. for an internal PKI). The Structurizr CLI trusts them in addition to the " +
+					"default trust store of its JVM, found from `JAVA_HOME` or the `java` executable in the `PATH`.",
+			},
+			"client_certificate": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded client certificate, or the path to a file containing it, presented for mutual TLS.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM encoded private key of the client certificate, or the path to a file containing it.",
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional: true,
+				Description: "The URL of the HTTP proxy used to reach the host (e.g. `http://proxy.internal:3128`). " +
+					"Defaults to the `HTTPS_PROXY` and `HTTP_PROXY` environment variables for the API client.",
+			},
+			"no_proxy": schema.StringAttribute{
+				Optional: true,
+				Description: "A comma-separated list of hosts and domains (e.g. `localhost,.internal`) reached without the proxy. " +
+					"Defaults to the `NO_PROXY` environment variable for the API client.",
+			},
+			"api_timeout": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"The maximum duration of a single request to the Structurizr API, retries excluded (e.g. `30s`). "+
+						"`0s` disables it. Defaults to `%s`.",
+					api.DefaultTimeout,
+				),
+			},
+			"cli_dir": schema.StringAttribute{
+				Optional: true,
+				Description: "The directory where the embedded Structurizr CLI is extracted, one subdirectory per version. " +
+					"Defaults to `terraform-provider-structurizr` in the user cache directory (e.g. `~/.cache` on Linux).",
+			},
+			"preserve_layout": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether the layout of the remote diagrams, such as the one made in the Structurizr UI, is " +
+					"merged into the pushed Workspaces. It requires the `cli` push client. Overridable per resource. " +
+					"Defaults to `false`.",
+			},
+			"archive": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether the previous version of a Workspace is archived into the `archive_dir` before " +
+					"being replaced by a push. Overridable per resource. Defaults to `true`.",
+			},
+			"archive_dir": schema.StringAttribute{
+				Optional: true,
+				Description: "The directory the archives of the Workspaces are written to. Defaults to " +
+					"`terraform-provider-structurizr/archives` in the user cache directory (e.g. `~/.cache` on Linux).",
+			},
+			"archive_retention": schema.Int64Attribute{
+				Optional:   true,
+				Validators: []validator.Int64{int64validator.AtLeast(0)},
+				Description: fmt.Sprintf(
+					"The number of archives kept per Workspace, the oldest ones are removed after each push. "+
+						"`0` keeps all of them. Defaults to `%d`.",
+					client.DefaultArchiveRetention,
+				),
+			},
+			"validate_sources": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether the DSL/JSON sources of the Workspaces are validated and inspected by the " +
+					"Structurizr CLI at plan time, so an invalid source fails the plan rather than the apply. It " +
+					"requires the `cli` push client and a JVM where Terraform plans. Defaults to `true`.",
+			},
+			"cli_timeout": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"The maximum duration of a single run of the Structurizr CLI, retries excluded (e.g. `5m`). "+
+						"The CLI and its JVM are terminated once exceeded. `0s` disables it. Defaults to `%s`.",
+					cli.DefaultTimeout,
+				),
+			},
 		},
 	}
 }
@@ -101,6 +284,35 @@ func (p *Structurizr) Configure(ctx context.Context, req provider.ConfigureReque
 		)
 	}
 
+	if config.PushClient.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("push_client"),
+			"Unknown Structurizr Push Client",
+			"The provider cannot create a Structurizr client as there is an unknown configuration value for the push_client. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the STRUCTURIZR_PUSH_CLIENT environment variable.",
+		)
+	}
+
+	validateKnown(&resp.Diagnostics, "admin_api_key_file", "STRUCTURIZR_ADMIN_API_KEY_FILE", config.AdminAPIKeyFile)
+	validateKnown(&resp.Diagnostics, "credential_process", "STRUCTURIZR_CREDENTIAL_PROCESS", config.CredentialProcess)
+	validateKnown(&resp.Diagnostics, "max_retries", "STRUCTURIZR_MAX_RETRIES", config.MaxRetries)
+	validateKnown(&resp.Diagnostics, "max_parallel_operations", "STRUCTURIZR_MAX_PARALLEL_OPERATIONS", config.MaxParallelOperations)
+	validateKnown(&resp.Diagnostics, "min_retry_wait", "STRUCTURIZR_MIN_RETRY_WAIT", config.MinRetryWait)
+	validateKnown(&resp.Diagnostics, "max_retry_wait", "STRUCTURIZR_MAX_RETRY_WAIT", config.MaxRetryWait)
+	validateKnown(&resp.Diagnostics, "ca_bundle", "STRUCTURIZR_CA_BUNDLE", config.CABundle)
+	validateKnown(&resp.Diagnostics, "client_certificate", "STRUCTURIZR_CLIENT_CERTIFICATE", config.ClientCert)
+	validateKnown(&resp.Diagnostics, "client_key", "STRUCTURIZR_CLIENT_KEY", config.ClientKey)
+	validateKnown(&resp.Diagnostics, "proxy_url", "STRUCTURIZR_PROXY_URL", config.ProxyURL)
+	validateKnown(&resp.Diagnostics, "no_proxy", "STRUCTURIZR_NO_PROXY", config.NoProxy)
+	validateKnown(&resp.Diagnostics, "api_timeout", "STRUCTURIZR_API_TIMEOUT", config.APITimeout)
+	validateKnown(&resp.Diagnostics, "cli_timeout", "STRUCTURIZR_CLI_TIMEOUT", config.CLITimeout)
+	validateKnown(&resp.Diagnostics, "cli_dir", "STRUCTURIZR_CLI_DIR", config.CLIDir)
+	validateKnown(&resp.Diagnostics, "preserve_layout", "STRUCTURIZR_PRESERVE_LAYOUT", config.PreserveLayout)
+	validateKnown(&resp.Diagnostics, "archive", "STRUCTURIZR_ARCHIVE", config.Archive)
+	validateKnown(&resp.Diagnostics, "archive_dir", "STRUCTURIZR_ARCHIVE_DIR", config.ArchiveDir)
+	validateKnown(&resp.Diagnostics, "archive_retention", "STRUCTURIZR_ARCHIVE_RETENTION", config.ArchiveRetention)
+	validateKnown(&resp.Diagnostics, "validate_sources", "STRUCTURIZR_VALIDATE_SOURCES", config.ValidateSources)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -109,7 +321,7 @@ func (p *Structurizr) Configure(ctx context.Context, req provider.ConfigureReque
 	// with Terraform configuration value if set.
 
 	host := os.Getenv("STRUCTURIZR_HOST")
-	adminApiKey := os.Getenv("STRUCTURIZR_ADMIN_API_KEY")
+	pushClient := os.Getenv("STRUCTURIZR_PUSH_CLIENT")
 
 	var (
 		tlsInsecure bool
@@ -131,15 +343,84 @@ func (p *Structurizr) Configure(ctx context.Context, req provider.ConfigureReque
 		host = config.Host.ValueString()
 	}
 
-	if !config.AdminAPIKey.IsNull() {
-		adminApiKey = config.AdminAPIKey.ValueString()
-	}
-
 	if !config.TLSInsecure.IsNull() {
 		v, _ := config.TLSInsecure.ToBoolValue(ctx)
 		tlsInsecure = v.ValueBool()
 	}
 
+	if !config.PushClient.IsNull() {
+		pushClient = config.PushClient.ValueString()
+	}
+
+	if pushClient == "" {
+		pushClient = pushClientCLI
+	}
+
+	adminApiKey := adminAPIKeyConfig(ctx, &resp.Diagnostics, config)
+
+	retryPolicy := retry.Policy{
+		MaxRetries: int(int64Config(
+			&resp.Diagnostics,
+			"max_retries",
+			"STRUCTURIZR_MAX_RETRIES",
+			config.MaxRetries,
+			retry.DefaultMaxRetries,
+			0,
+		)),
+		MinWait: durationConfig(
+			&resp.Diagnostics,
+			"min_retry_wait",
+			"STRUCTURIZR_MIN_RETRY_WAIT",
+			config.MinRetryWait,
+			retry.DefaultMinWait,
+		),
+		MaxWait: durationConfig(
+			&resp.Diagnostics,
+			"max_retry_wait",
+			"STRUCTURIZR_MAX_RETRY_WAIT",
+			config.MaxRetryWait,
+			retry.DefaultMaxWait,
+		),
+	}
+
+	transportConfig := transport.Config{
+		TLSInsecure:       tlsInsecure,
+		CABundle:          pemConfig(&resp.Diagnostics, "ca_bundle", "STRUCTURIZR_CA_BUNDLE", config.CABundle),
+		ClientCertificate: pemConfig(&resp.Diagnostics, "client_certificate", "STRUCTURIZR_CLIENT_CERTIFICATE", config.ClientCert),
+		ClientKey:         pemConfig(&resp.Diagnostics, "client_key", "STRUCTURIZR_CLIENT_KEY", config.ClientKey),
+		ProxyURL:          urlConfig(&resp.Diagnostics, "proxy_url", "STRUCTURIZR_PROXY_URL", config.ProxyURL),
+		NoProxy:           stringConfig("STRUCTURIZR_NO_PROXY", config.NoProxy),
+	}
+
+	maxParallelOperations := int64Config(
+		&resp.Diagnostics,
+		"max_parallel_operations",
+		"STRUCTURIZR_MAX_PARALLEL_OPERATIONS",
+		config.MaxParallelOperations,
+		client.DefaultMaxParallelOperations,
+		1,
+	)
+
+	pushConfig := client.PushConfig{
+		Defaults: api.PushOptions{
+			Merge:      boolConfig(&resp.Diagnostics, "preserve_layout", "STRUCTURIZR_PRESERVE_LAYOUT", config.PreserveLayout, false),
+			Archive:    boolConfig(&resp.Diagnostics, "archive", "STRUCTURIZR_ARCHIVE", config.Archive, true),
+			ArchiveDir: stringConfig("STRUCTURIZR_ARCHIVE_DIR", config.ArchiveDir),
+		},
+		ArchiveRetention: int(int64Config(
+			&resp.Diagnostics,
+			"archive_retention",
+			"STRUCTURIZR_ARCHIVE_RETENTION",
+			config.ArchiveRetention,
+			client.DefaultArchiveRetention,
+			0,
+		)),
+		Validate: boolConfig(&resp.Diagnostics, "validate_sources", "STRUCTURIZR_VALIDATE_SOURCES", config.ValidateSources, true),
+	}
+
+	apiTimeout := durationConfig(&resp.Diagnostics, "api_timeout", "STRUCTURIZR_API_TIMEOUT", config.APITimeout, api.DefaultTimeout)
+	cliTimeout := durationConfig(&resp.Diagnostics, "cli_timeout", "STRUCTURIZR_CLI_TIMEOUT", config.CLITimeout, cli.DefaultTimeout)
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
@@ -163,40 +444,132 @@ func (p *Structurizr) Configure(ctx context.Context, req provider.ConfigureReque
 		)
 	}
 
+	// The admin API key is only required to create, list and delete workspaces, so the content of existing workspaces
+	// can be managed on the cloud service, which has no admin API
 	if adminApiKey == "" {
+		tflog.Info(ctx, "No Structurizr admin API key configured, only the content of existing workspaces can be managed")
+	}
+
+	if retryPolicy.MinWait > retryPolicy.MaxWait {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("admin_api_key"),
-			"Missing Structurizr Admin API Key",
-			"The provider cannot create a Structurizr client as there is a missing or empty value for the Structurizr Admin API Key. "+
-				"Set the admin_api_key value in the configuration or use the STRUCTURIZR_ADMIN_API_KEY environment variable. "+
-				"If either is already set, ensure the value is not empty.",
+			path.Root("min_retry_wait"),
+			"Invalid Structurizr Retry Wait",
+			fmt.Sprintf(
+				"The min_retry_wait (%s) must not be greater than the max_retry_wait (%s).",
+				retryPolicy.MinWait,
+				retryPolicy.MaxWait,
+			),
+		)
+	}
+
+	if maxParallelOperations < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_parallel_operations"),
+			"Invalid Structurizr Max Parallel Operations",
+			fmt.Sprintf("The max_parallel_operations (%d) must be at least 1.", maxParallelOperations),
+		)
+	}
+
+	if pushConfig.ArchiveRetention < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("archive_retention"),
+			"Invalid Structurizr Archive Retention",
+			fmt.Sprintf("The archive_retention (%d) must not be negative.", pushConfig.ArchiveRetention),
 		)
 	}
 
-	cliWorkingDir, err := cli.WorkingDir(ctx)
+	if pushConfig.Defaults.ArchiveDir == "" {
+		baseDir, err := cli.DefaultBaseDir()
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("archive_dir"),
+				"Unable to locate the Structurizr archive directory",
+				"Set the archive_dir value in the configuration or use the STRUCTURIZR_ARCHIVE_DIR environment variable.\n\n"+
+					"Error: "+err.Error(),
+			)
+		}
+		pushConfig.Defaults.ArchiveDir = filepath.Join(baseDir, "archives")
+	}
+
+	if pushClient != pushClientCLI && pushClient != pushClientAPI {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("push_client"),
+			"Invalid Structurizr Push Client",
+			fmt.Sprintf(
+				"The provider cannot create a Structurizr client as the push client %q is not supported. "+
+					"Set the push_client value in the configuration or the STRUCTURIZR_PUSH_CLIENT environment variable to either %q or %q.",
+				pushClient,
+				pushClientCLI,
+				pushClientAPI,
+			),
+		)
+	}
+
+	if (transportConfig.ClientCertificate == nil) != (transportConfig.ClientKey == nil) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_certificate"),
+			"Invalid Structurizr Client Certificate",
+			"The client_certificate and the client_key must be set together, either in the configuration "+
+				"or with the STRUCTURIZR_CLIENT_CERTIFICATE and STRUCTURIZR_CLIENT_KEY environment variables.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient, err := api.NewClient(&api.Config{
+		AdminAPIKey: adminApiKey,
+		BaseURL:     baseURL,
+		Transport:   transportConfig,
+		UserAgent:   api.DefaultUserAgent,
+		Retry:       retryPolicy,
+		Timeout:     apiTimeout,
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to setting up the Structurizr CLI working directory",
-			"An unexpected error occurred when setting up the Structurizr CLI working directory. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
+			"Unable to configure the Structurizr TLS settings",
+			"The ca_bundle, client_certificate or client_key could not be loaded.\n\n"+
 				"Error: "+err.Error(),
 		)
+		return
 	}
 
-	if resp.Diagnostics.HasError() {
-		return
+	// The Structurizr CLI is only extracted when it is used to push workspaces, so it does not require a JVM otherwise
+	var workspaceClient client.WorkspaceClient = apiClient
+	if pushClient == pushClientCLI {
+		cliWorkingDir, err := cli.WorkingDir(ctx, stringConfig("STRUCTURIZR_CLI_DIR", config.CLIDir))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("cli_dir"),
+				"Unable to setting up the Structurizr CLI working directory",
+				fmt.Sprintf(
+					"An unexpected error occurred when setting up the working directory of the Structurizr CLI %s. "+
+						"Set the cli_dir value in the configuration or use the STRUCTURIZR_CLI_DIR environment variable "+
+						"to extract it into a writable directory. "+
+						"If the error is not clear, please contact the provider developers.\n\n"+
+						"Error: %s",
+					cli.Version(),
+					err,
+				),
+			)
+			return
+		}
+
+		workspaceClient = cli.NewClient(
+			&cli.Config{
+				BaseURL:    baseURL,
+				WorkingDir: cliWorkingDir,
+				Retry:      retryPolicy,
+				Transport:  transportConfig,
+				Timeout:    cliTimeout,
+			},
+			cli.DefaultCmdExec,
+		)
 	}
 
 	// Create a new Structurizr client using the configuration values
-	m := client.NewManager(
-		api.NewClient(&api.Config{
-			AdminAPIKey: adminApiKey,
-			BaseURL:     baseURL,
-			TLSInsecure: tlsInsecure,
-			UserAgent:   api.DefaultUserAgent,
-		}),
-		cli.NewClient(&cli.Config{BaseURL: baseURL, WorkingDir: cliWorkingDir}, cli.DefaultCmdExec),
-	)
+	m := client.NewManager(apiClient, workspaceClient, int(maxParallelOperations), pushConfig)
 
 	resp.DataSourceData = m
 	resp.ResourceData = m
@@ -206,6 +579,8 @@ func (p *Structurizr) Configure(ctx context.Context, req provider.ConfigureReque
 func (p *Structurizr) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewWorkspaceResource,
+		NewWorkspaceLockResource,
+		NewWorkspaceContentResource,
 	}
 }
 
@@ -213,5 +588,14 @@ func (p *Structurizr) Resources(_ context.Context) []func() resource.Resource {
 func (p *Structurizr) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewWorkspacesDataSource,
+		NewWorkspaceContentDataSource,
+		NewWorkspaceVersionsDataSource,
+	}
+}
+
+// Functions registers all available functions that can be called from the configuration
+func (p *Structurizr) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewWorkspaceChecksumFunction,
 	}
 }
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validateKnown adds an error when a configuration value of the provider is not known yet
+func validateKnown(diags *diag.Diagnostics, name string, env string, value attr.Value) {
+	if !value.IsUnknown() {
+		return
+	}
+
+	diags.AddAttributeError(
+		path.Root(name),
+		fmt.Sprintf("Unknown Structurizr %s", name),
+		fmt.Sprintf(
+			"The provider cannot create a Structurizr client as there is an unknown configuration value for the %s. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, "+
+				"or use the %s environment variable.",
+			name,
+			env,
+		),
+	)
+}
+
+// int64Config returns the configuration value, otherwise the value of the environment variable or the fallback.
+// The environment variable must be at least min, as the validators of the attribute do not apply to it.
+func int64Config(diags *diag.Diagnostics, name string, env string, value types.Int64, fallback int64, min int64) int64 {
+	if !value.IsNull() {
+		return value.ValueInt64()
+	}
+
+	v := os.Getenv(env)
+	if v == "" {
+		return fallback
+	}
+
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(name),
+			fmt.Sprintf("Unable to parse %s environment variable", env),
+			fmt.Sprintf("The %s environment variable must be a whole number.\n\nError: %s", env, err),
+		)
+
+		return fallback
+	}
+
+	if i < min {
+		expected := fmt.Sprintf("%d or greater", min)
+		if min == 0 {
+			expected = "zero or greater"
+		}
+
+		diags.AddAttributeError(
+			path.Root(name),
+			fmt.Sprintf("Invalid %s environment variable", env),
+			fmt.Sprintf("The %s environment variable must be %s, got: %d.", env, expected, i),
+		)
+
+		return fallback
+	}
+
+	return i
+}
+
+// boolConfig returns the configuration value, otherwise the value of the environment variable or the fallback
+func boolConfig(diags *diag.Diagnostics, name string, env string, value types.Bool, fallback bool) bool {
+	if !value.IsNull() {
+		return value.ValueBool()
+	}
+
+	v := os.Getenv(env)
+	if v == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(name),
+			fmt.Sprintf("Unable to parse %s environment variable", env),
+			fmt.Sprintf("The %s environment variable must be either \"true\" or \"false\".\n\nError: %s", env, err),
+		)
+	}
+
+	return b
+}
+
+// durationConfig returns the configuration value, otherwise the value of the environment variable or the fallback
+func durationConfig(diags *diag.Diagnostics, name string, env string, value types.String, fallback time.Duration) time.Duration {
+	v, source := value.ValueString(), name
+	if value.IsNull() {
+		v, source = os.Getenv(env), env+" environment variable"
+	}
+
+	if v == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(name),
+			fmt.Sprintf("Unable to parse %s", source),
+			fmt.Sprintf("The %s must be a duration such as \"30s\" or \"2m\".\n\nError: %s", source, err),
+		)
+	} else if d < 0 {
+		diags.AddAttributeError(
+			path.Root(name),
+			fmt.Sprintf("Invalid %s", source),
+			fmt.Sprintf("The %s must be zero or greater, got: %s.", source, v),
+		)
+	}
+
+	return d
+}
+
+// stringConfig returns the configuration value, otherwise the value of the environment variable
+func stringConfig(env string, value types.String) string {
+	if !value.IsNull() {
+		return value.ValueString()
+	}
+
+	return os.Getenv(env)
+}
+
+// pemConfig returns PEM encoded data from the configuration value or the environment variable,
+// which contains either the data itself or the path to a file containing it
+func pemConfig(diags *diag.Diagnostics, name string, env string, value types.String) []byte {
+	v := stringConfig(env, value)
+	if v == "" {
+		return nil
+	}
+
+	if strings.Contains(v, "-----BEGIN ") {
+		return []byte(v)
+	}
+
+	data, err := os.ReadFile(v)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(name),
+			fmt.Sprintf("Unable to read %s", name),
+			fmt.Sprintf(
+				"The %s must be PEM encoded data or the path to a file containing it.\n\nError: %s",
+				name,
+				err,
+			),
+		)
+	}
+
+	return data
+}
+
+// urlConfig returns the absolute URL from the configuration value, otherwise from the environment variable
+func urlConfig(diags *diag.Diagnostics, name string, env string, value types.String) *url.URL {
+	v := stringConfig(env, value)
+	if v == "" {
+		return nil
+	}
+
+	u, err := url.Parse(v)
+	if err == nil && (u.Scheme == "" || u.Host == "") {
+		err = fmt.Errorf("missing scheme or host in %q", v)
+	}
+
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(name),
+			fmt.Sprintf("Unable to parse %s", name),
+			fmt.Sprintf("The %s must be an absolute URL such as \"http://proxy:3128\".\n\nError: %s", name, err),
+		)
+		return nil
+	}
+
+	return u
+}
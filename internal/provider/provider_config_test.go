@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInt64Config(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    types.Int64
+		env      string
+		expected int64
+		wantErr  bool
+	}{
+		{"Given a configuration value", types.Int64Value(5), "7", 5, false},
+		{"Given an environment variable", types.Int64Null(), "7", 7, false},
+		{"Given no value", types.Int64Null(), "", 3, false},
+		{"Given an invalid environment variable", types.Int64Null(), "many", 3, true},
+		{"Given a negative environment variable", types.Int64Null(), "-1", 3, true},
+		{"Given an environment variable below the minimum", types.Int64Null(), "0", 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STRUCTURIZR_TEST", tt.env)
+
+			var diags diag.Diagnostics
+			actual := int64Config(&diags, "test", "STRUCTURIZR_TEST", tt.value, 3, 1)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.wantErr, diags.HasError())
+			for _, d := range diags {
+				assert.NotContains(t, d.Detail(), "<nil>")
+			}
+		})
+	}
+}
+
+func TestBoolConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    types.Bool
+		env      string
+		expected bool
+		wantErr  bool
+	}{
+		{"Given a configuration value", types.BoolValue(false), "true", false, false},
+		{"Given an environment variable", types.BoolNull(), "false", false, false},
+		{"Given no value", types.BoolNull(), "", true, false},
+		{"Given an invalid environment variable", types.BoolNull(), "sometimes", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STRUCTURIZR_TEST", tt.env)
+
+			var diags diag.Diagnostics
+			actual := boolConfig(&diags, "test", "STRUCTURIZR_TEST", tt.value, true)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.wantErr, diags.HasError())
+		})
+	}
+}
+
+func TestDurationConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    types.String
+		env      string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"Given a configuration value", types.StringValue("2s"), "5s", 2 * time.Second, false},
+		{"Given an environment variable", types.StringNull(), "5s", 5 * time.Second, false},
+		{"Given no value", types.StringNull(), "", time.Second, false},
+		{"Given an invalid configuration value", types.StringValue("soon"), "", 0, true},
+		{"Given a negative configuration value", types.StringValue("-1s"), "", -time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STRUCTURIZR_TEST", tt.env)
+
+			var diags diag.Diagnostics
+			actual := durationConfig(&diags, "test", "STRUCTURIZR_TEST", tt.value, time.Second)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.wantErr, diags.HasError())
+			for _, d := range diags {
+				assert.NotContains(t, d.Detail(), "<nil>")
+			}
+		})
+	}
+}
+
+func TestPEMConfig(t *testing.T) {
+	pem := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(file, []byte(pem), 0600))
+
+	tests := []struct {
+		name     string
+		value    types.String
+		env      string
+		expected []byte
+		wantErr  bool
+	}{
+		{"Given PEM data", types.StringValue(pem), "", []byte(pem), false},
+		{"Given a file path", types.StringValue(file), "", []byte(pem), false},
+		{"Given an environment variable", types.StringNull(), file, []byte(pem), false},
+		{"Given no value", types.StringNull(), "", nil, false},
+		{"Given a missing file", types.StringValue(filepath.Join(t.TempDir(), "missing.pem")), "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STRUCTURIZR_TEST", tt.env)
+
+			var diags diag.Diagnostics
+			actual := pemConfig(&diags, "test", "STRUCTURIZR_TEST", tt.value)
+
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.wantErr, diags.HasError())
+		})
+	}
+}
+
+func TestURLConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    types.String
+		env      string
+		expected string
+		wantErr  bool
+	}{
+		{"Given a configuration value", types.StringValue("http://proxy:3128"), "http://env:3128", "http://proxy:3128", false},
+		{"Given an environment variable", types.StringNull(), "http://env:3128", "http://env:3128", false},
+		{"Given no value", types.StringNull(), "", "", false},
+		{"Given a relative URL", types.StringValue("proxy:3128"), "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STRUCTURIZR_TEST", tt.env)
+
+			var diags diag.Diagnostics
+			actual := urlConfig(&diags, "test", "STRUCTURIZR_TEST", tt.value)
+
+			if tt.expected == "" {
+				assert.Nil(t, actual)
+			} else {
+				assert.Equal(t, tt.expected, actual.String())
+			}
+			assert.Equal(t, tt.wantErr, diags.HasError())
+		})
+	}
+}
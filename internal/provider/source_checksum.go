@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/source"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"io/fs"
+)
+
+// sourceChecksum returns a plan modifier computing the checksum of the source of a workspace and of its dependencies
+// when source_checksum is not configured, so editing any file included by the source plans a push
+func sourceChecksum() planmodifier.String {
+	return sourceChecksumModifier{}
+}
+
+// sourceChecksumModifier is the plan modifier of source_checksum
+type sourceChecksumModifier struct{}
+
+// Description returns a plain text description of the modifier's behavior.
+func (m sourceChecksumModifier) Description(_ context.Context) string {
+	return "When not configured, the checksum is computed from the source and the files it includes."
+}
+
+// MarkdownDescription returns a markdown formatted description of the modifier's behavior.
+func (m sourceChecksumModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+// PlanModifyString computes the checksum of the planned source. It is unknown until the apply when the source is not
+// known yet, or does not exist yet such as when it is written by another resource.
+func (m sourceChecksumModifier) PlanModifyString(
+	ctx context.Context,
+	req planmodifier.StringRequest,
+	resp *planmodifier.StringResponse,
+) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+
+	var file types.String
+	if resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("source"), &file)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case file.IsUnknown():
+		resp.PlanValue = types.StringUnknown()
+	case file.IsNull():
+		resp.PlanValue = types.StringNull()
+	default:
+		sum, err := source.Checksum(file.ValueString())
+		switch {
+		case err == nil:
+			resp.PlanValue = types.StringValue(sum)
+		case errors.Is(err, fs.ErrNotExist):
+			resp.PlanValue = types.StringUnknown()
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("source"),
+				"Error computing Workspace source checksum",
+				fmt.Sprintf(
+					"Failed to compute the checksum of %s with error: %s. Set source_checksum when the dependencies "+
+						"of the source cannot be read at plan time.",
+					file.ValueString(),
+					err,
+				),
+			)
+		}
+	}
+}
+
+// resolveSourceChecksum computes the checksum of a source which was not known at plan time, once it has been pushed
+func resolveSourceChecksum(file types.String, checksum types.String) (types.String, error) {
+	if !checksum.IsUnknown() {
+		return checksum, nil
+	}
+
+	if file.IsNull() {
+		return types.StringNull(), nil
+	}
+
+	sum, err := source.Checksum(file.ValueString())
+	if err != nil {
+		return types.StringNull(), err
+	}
+
+	return types.StringValue(sum), nil
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/source"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &workspaceChecksumFunction{}
+
+// NewWorkspaceChecksumFunction is a helper function to simplify the provider implementation.
+func NewWorkspaceChecksumFunction() function.Function {
+	return &workspaceChecksumFunction{}
+}
+
+// workspaceChecksumFunction is the function implementation.
+type workspaceChecksumFunction struct{}
+
+// Metadata returns the function name.
+func (f *workspaceChecksumFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "workspace_checksum"
+}
+
+// Definition defines the parameters and the return of the function.
+func (f *workspaceChecksumFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checksum of a workspace source and of its dependencies",
+		Description: "Returns the SHA-256 digest of a DSL/JSON workspace source and of every file it depends on, " +
+			"pulled in recursively by the `!include`, `!docs` and `!adrs` directives or extended by the workspace. " +
+			"It is the checksum computed when the `source_checksum` of a workspace is not configured.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "path",
+				Description: "The path of the DSL/JSON file representing a Workspace.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run computes the checksum of the workspace source.
+func (f *workspaceChecksumFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+	if resp.Error = req.Arguments.Get(ctx, &name); resp.Error != nil {
+		return
+	}
+
+	sum, err := source.Checksum(name)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Failed to compute the checksum of %s with error: %s", name, err))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, sum)
+}
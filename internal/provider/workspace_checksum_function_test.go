@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"github.com/fstaoe/terraform-provider-structurizr/internal/source"
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestFunctionWorkspaceChecksum(t *testing.T) {
+	name := writeWorkspaceWithIncludes(t)
+	expected, err := source.Checksum(name)
+	assert.NoError(t, err)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		TerraformVersionChecks:   []tfversion.TerraformVersionCheck{tfversion.SkipBelow(tfversion.Version1_8_0)},
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccFunctionWorkspaceChecksumConfig(),
+				ConfigVariables: config.Variables{"path": config.StringVariable(name)},
+				Check:           resource.TestCheckOutput("checksum", expected),
+			},
+			{
+				Config:          testAccFunctionWorkspaceChecksumConfig(),
+				ConfigVariables: config.Variables{"path": config.StringVariable(filepath.Join(t.TempDir(), "missing.dsl"))},
+				ExpectError:     regexp.MustCompile(`Failed to compute the checksum`),
+			},
+		},
+	})
+}
+
+// writeWorkspaceWithIncludes writes a DSL workspace including other files into a temporary directory
+func writeWorkspaceWithIncludes(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"workspace.dsl": "workspace \"Workspace DSL\" \"Managed Workspace by DSL\" {\n" +
+			"    !docs docs\n" +
+			"    model {\n" +
+			"        !include model.dsl\n" +
+			"    }\n" +
+			"}\n",
+		"model.dsl":      "user = person \"User\"\n",
+		"docs/readme.md": "# Workspace DSL\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		assert.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+
+	return filepath.Join(dir, "workspace.dsl")
+}
+
+func testAccFunctionWorkspaceChecksumConfig() string {
+	return `
+variable "path" {}
+
+output "checksum" {
+    value = provider::structurizr::workspace_checksum(var.path)
+}
+`
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// WorkspaceContentModel represents the content of a workspace stored in the structurizr
+type WorkspaceContentModel struct {
+	ID                types.Int64  `tfsdk:"id"`
+	APIKey            types.String `tfsdk:"api_key"`
+	APISecret         types.String `tfsdk:"api_secret"`
+	Passphrase        types.String `tfsdk:"passphrase"`
+	Name              types.String `tfsdk:"name"`
+	Description       types.String `tfsdk:"description"`
+	JSON              types.String `tfsdk:"json"`
+	Encrypted         types.Bool   `tfsdk:"encrypted"`
+	Revision          types.Int64  `tfsdk:"revision"`
+	LastModifiedDate  types.String `tfsdk:"last_modified_date"`
+	LastModifiedUser  types.String `tfsdk:"last_modified_user"`
+	LastModifiedAgent types.String `tfsdk:"last_modified_agent"`
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                     = &workspaceContentDataSource{}
+	_ datasource.DataSourceWithConfigure        = &workspaceContentDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &workspaceContentDataSource{}
+)
+
+// NewWorkspaceContentDataSource is a helper function to simplify the provider implementation.
+func NewWorkspaceContentDataSource() datasource.DataSource {
+	return &workspaceContentDataSource{}
+}
+
+// workspaceContentDataSource is the data source implementation.
+type workspaceContentDataSource struct {
+	client *client.Manager
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *workspaceContentDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Manager)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected *client.Manager, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+
+		return
+	}
+
+	d.client = c
+}
+
+// ConfigValidators returns a list of functions which will all be performed during validation.
+func (d *workspaceContentDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		// Validate the workspace credentials are either both null or both known values.
+		datasourcevalidator.RequiredTogether(
+			path.MatchRoot("api_key"),
+			path.MatchRoot("api_secret"),
+		),
+	}
+}
+
+// Metadata returns the data source type name. It can be used to register other type of information
+func (d *workspaceContentDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_content"
+}
+
+// Schema defines the schema for the data source.
+func (d *workspaceContentDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The identifier of the Workspace to retrieve the content from.",
+			},
+			"api_key": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "The API key specific to the Workspace. " +
+					"When omitted, it is looked up using the admin API key of the provider.",
+			},
+			"api_secret": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "The API secret key specific to the Workspace. " +
+					"When omitted, it is looked up using the admin API key of the provider.",
+			},
+			"passphrase": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The passphrase used to decrypt the Workspace when the client-side encryption is enabled.",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the Workspace",
+			},
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "The description of the Workspace explaining roughly what it is about.",
+			},
+			"json": schema.StringAttribute{
+				Computed: true,
+				Description: "The JSON representation of the Workspace. " +
+					"It contains the encrypted envelope when the Workspace is encrypted and no passphrase is provided.",
+			},
+			"encrypted": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the Workspace is encrypted on the client-side.",
+			},
+			"revision": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The revision of the Workspace, incremented by the remote server on every change.",
+			},
+			"last_modified_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "The date when the Workspace was last modified.",
+			},
+			"last_modified_user": schema.StringAttribute{
+				Computed:    true,
+				Description: "The user who last modified the Workspace.",
+			},
+			"last_modified_agent": schema.StringAttribute{
+				Computed:    true,
+				Description: "The agent (e.g. structurizr-cli, structurizr-web) which last modified the Workspace.",
+			},
+		},
+	}
+}
+
+// Read fetches the Terraform state with the latest data.
+func (d *workspaceContentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state WorkspaceContentModel
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("[READ] State: %s", state))
+
+	id := state.ID.ValueInt64()
+	key, secret, err := workspaceCredentials(ctx, d.client, id, state.APIKey, state.APISecret)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Unable to retrieve structurizr workspace credentials",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %d) credentials with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	content, err := d.client.GetWorkspace(ctx, id, key, secret, state.Passphrase.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read structurizr workspace content",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %d) content with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	state.Name = types.StringValue(content.Name)
+	state.Description = types.StringValue(content.Description)
+	state.JSON = types.StringValue(string(content.Raw))
+	state.Encrypted = types.BoolValue(content.IsEncrypted())
+	state.Revision = types.Int64Value(content.Revision)
+	state.LastModifiedDate = types.StringValue(content.LastModifiedDate)
+	state.LastModifiedUser = types.StringValue(content.LastModifiedUser)
+	state.LastModifiedAgent = types.StringValue(content.LastModifiedAgent)
+
+	tflog.Trace(ctx, fmt.Sprintf("[READ] Storing Workspace content: %+v", state))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
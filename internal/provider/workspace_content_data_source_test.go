@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"github.com/fstaoe/terraform-provider-structurizr/internal/acctest"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/util"
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"net/http"
+	"testing"
+)
+
+func TestDataSourceWorkspaceContent_Basic(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockDataSourceWorkspaceContentBasic,
+				ContentType: "application/json",
+			},
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccDataSourceWorkspaceContentConfig(),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "id", "1"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "name", "Workspace JSON"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "description", "Managed Workspace by JSON"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "encrypted", "false"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "revision", "3"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "last_modified_date", "2024-05-01T10:00:00Z"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "last_modified_user", "admin"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "last_modified_agent", "structurizr-cli/2024.03.03"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "json", acctest.MockDataSourceWorkspaceContentBasic),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceWorkspaceContent_Encrypted(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockDataSourceWorkspacesBasic,
+				ContentType: "application/json",
+			},
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockDataSourceWorkspaceContentEncrypted,
+				ContentType: "application/json",
+			},
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccDataSourceWorkspaceContentConfigEncrypted(),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "id", "1"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "name", "Encrypted Workspace"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "encrypted", "true"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_content.test", "revision", "4"),
+					resource.TestCheckResourceAttr(
+						"data.structurizr_workspace_content.test",
+						"json",
+						`{"id":1,"name":"Encrypted Workspace","model":{}}`,
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceWorkspaceContentConfig() string {
+	return util.ConfigCompose(testAccProvider(), `
+data "structurizr_workspace_content" "test" {
+    id         = 1
+    api_key    = "691e0542-5c4d-4f74-be4a-38134a0aa0bf"
+    api_secret = "8497f68e-75b9-431b-b067-cf86a074205c"
+}
+`)
+}
+
+func testAccDataSourceWorkspaceContentConfigEncrypted() string {
+	return util.ConfigCompose(testAccProvider(), `
+data "structurizr_workspace_content" "test" {
+    id         = 1
+    passphrase = "structurizr"
+}
+`)
+}
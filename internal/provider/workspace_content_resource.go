@@ -0,0 +1,374 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strconv"
+	"strings"
+)
+
+// WorkspaceContentResourceModel represents the content of an existing workspace in the structurizr
+type WorkspaceContentResourceModel struct {
+	ID               types.Int64    `tfsdk:"id"`
+	APIKey           types.String   `tfsdk:"api_key"`
+	APISecret        types.String   `tfsdk:"api_secret"`
+	Source           types.String   `tfsdk:"source"`
+	SourceChecksum   types.String   `tfsdk:"source_checksum"`
+	SourcePassphrase types.String   `tfsdk:"source_passphrase"`
+	PreserveLayout   types.Bool     `tfsdk:"preserve_layout"`
+	Archive          types.Bool     `tfsdk:"archive"`
+	Name             types.String   `tfsdk:"name"`
+	Description      types.String   `tfsdk:"description"`
+	Revision         types.Int64    `tfsdk:"revision"`
+	RemoteRevision   types.Int64    `tfsdk:"remote_revision"`
+	Timeouts         timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &workspaceContentResource{}
+	_ resource.ResourceWithConfigure   = &workspaceContentResource{}
+	_ resource.ResourceWithImportState = &workspaceContentResource{}
+	_ resource.ResourceWithModifyPlan  = &workspaceContentResource{}
+)
+
+// NewWorkspaceContentResource is a helper function to simplify the provider implementation.
+func NewWorkspaceContentResource() resource.Resource {
+	return &workspaceContentResource{}
+}
+
+// workspaceContentResource is the resource implementation.
+type workspaceContentResource struct {
+	clientManager *client.Manager
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *workspaceContentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	m, ok := req.ProviderData.(*client.Manager)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected resource Configure Type",
+			fmt.Sprintf(
+				"Expected *client.Manager, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	r.clientManager = m
+}
+
+// Metadata returns the resource type name. It can be used to register other type of information.
+func (r *workspaceContentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_content"
+}
+
+// Schema defines the schema for the resource.
+func (r *workspaceContentResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Pushes the content of an existing Workspace from its source using the API key and secret of the " +
+			"Workspace only, without the admin API key of the provider. It is meant for the cloud service, or " +
+			"Workspaces created outside of Terraform. Destroying the resource only removes it from the state, " +
+			"the Workspace and its content are kept on the remote server.",
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Update: true}),
+		},
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Required:      true,
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+				Description:   "The identifier of the existing Workspace.",
+			},
+			"api_key": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				Description: "The API key specific to the Workspace.",
+			},
+			"api_secret": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				Description: "The API secret key specific to the Workspace.",
+			},
+			"source": schema.StringAttribute{
+				Required:    true,
+				Description: "The DSL/JSON file representing the Workspace.",
+			},
+			"source_checksum": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "The checksum of the source file, the source is pushed again when it changes. It defaults " +
+					"to the SHA-256 digest of the source and of every file it includes through the `!include`, `!docs` " +
+					"and `!adrs` directives, as returned by the `workspace_checksum` function.",
+				PlanModifiers: []planmodifier.String{sourceChecksum()},
+			},
+			"source_passphrase": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The passphrase to use when the client-side encryption is enabled on the workspace.",
+			},
+			"preserve_layout": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether the layout of the remote diagrams, such as the one made in the Structurizr UI, is " +
+					"merged into the pushed source. Defaults to the `preserve_layout` of the provider.",
+			},
+			"archive": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether the previous version of the Workspace is archived into the `archive_dir` of the " +
+					"provider before each push. Defaults to the `archive` of the provider.",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "The name of the Workspace",
+			},
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "The description of the Workspace explaining roughly what it is about.",
+			},
+			"revision": schema.Int64Attribute{
+				Computed: true,
+				Description: "The revision of the Workspace recorded after its source was pushed. When the " +
+					"`remote_revision` differs (e.g. the Workspace was edited in the Structurizr UI), the drift is " +
+					"shown in the plan and applying it pushes the source again, overwriting the out-of-band changes.",
+			},
+			"remote_revision": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The latest revision of the Workspace on the remote server, refreshed on every read.",
+			},
+		},
+	}
+}
+
+// ModifyPlan plans a push of the source when the content of the workspace was modified outside of Terraform, which
+// overwrites the out-of-band changes.
+func (r *workspaceContentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var state WorkspaceContentResourceModel
+	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !revisionDrifted(state.Revision, state.RemoteRevision) {
+		return
+	}
+
+	for _, attr := range []string{"name", "description"} {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attr), types.StringUnknown())...)
+	}
+	for _, attr := range []string{"revision", "remote_revision"} {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attr), types.Int64Unknown())...)
+	}
+}
+
+// Create pushes the source of the workspace and sets the initial Terraform state.
+func (r *workspaceContentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan WorkspaceContentResourceModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Create(ctx, defaultWorkspaceCreateTimeout)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tflog.Trace(ctx, fmt.Sprintf("[CREATE] Plan: %s", plan))
+
+	r.push(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("[CREATE] Storing Workspace content: %+v", plan))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *workspaceContentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state WorkspaceContentResourceModel
+	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Read(ctx, defaultWorkspaceReadTimeout)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tflog.Trace(ctx, fmt.Sprintf("[READ] State %s", state))
+
+	content, err := r.clientManager.GetWorkspace(
+		ctx,
+		state.ID.ValueInt64(),
+		state.APIKey.ValueString(),
+		state.APISecret.ValueString(),
+		"",
+	)
+	if errors.Is(err, model.APIErrNotFound) {
+		// The workspace has been deleted outside of Terraform, removing it from the state lets Terraform plan a new push
+		tflog.Warn(ctx, fmt.Sprintf("Workspace (id: %s) not found on remote server, removing its content from the state", state.ID))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error retrieving Workspace",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %s) content with error: %s", state.ID, errorDetail(err)),
+		)
+		return
+	}
+
+	// The revision recorded after the last push is kept, so a remote revision which differs is planned by ModifyPlan
+	// as a push of the source
+	state.Name = types.StringValue(content.Name)
+	state.Description = types.StringValue(content.Description)
+	state.RemoteRevision = types.Int64Value(content.Revision)
+	if state.Revision.IsNull() {
+		state.Revision = state.RemoteRevision
+	}
+
+	if revisionDrifted(state.Revision, state.RemoteRevision) {
+		tflog.Warn(ctx, fmt.Sprintf(
+			"Workspace (id: %s) was modified outside of Terraform (revision %s, remote revision %s), "+
+				"its source will be pushed again",
+			state.ID,
+			state.Revision,
+			state.RemoteRevision,
+		))
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("[READ] Storing Workspace content: %+v", state))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update pushes the source of the workspace again and sets the updated Terraform state on success.
+func (r *workspaceContentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan WorkspaceContentResourceModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Update(ctx, defaultWorkspaceUpdateTimeout)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tflog.Trace(ctx, fmt.Sprintf("[UPDATE] Plan %s", plan))
+
+	r.push(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("[UPDATE] Storing Workspace content: %+v", plan))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete removes the Terraform state, the content cannot be deleted without deleting the workspace itself.
+func (r *workspaceContentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state WorkspaceContentResourceModel
+	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Workspace (id: %s) content removed from the state, it is kept on the remote server", state.ID))
+}
+
+// ImportState imports the content of an existing workspace from an identifier of the form <id>:<api_key>:<api_secret>,
+// its source is pushed on the next apply.
+func (r *workspaceContentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.SplitN(req.ID, ":", 3)
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if len(parts) != 3 || err != nil || parts[1] == "" || parts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Error parsing Workspace content identifier",
+			fmt.Sprintf("Expected an identifier of the form <id>:<api_key>:<api_secret>, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("api_key"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("api_secret"), parts[2])...)
+}
+
+// push pushes the source of the workspace and records the resulting name, description and revision in the model
+func (r *workspaceContentResource) push(
+	ctx context.Context,
+	m *WorkspaceContentResourceModel,
+	diags *diag.Diagnostics,
+) {
+	id, key, secret := m.ID.ValueInt64(), m.APIKey.ValueString(), m.APISecret.ValueString()
+
+	err := r.clientManager.PushWorkspace(
+		ctx,
+		id,
+		key,
+		secret,
+		m.SourcePassphrase.ValueString(),
+		m.Source.ValueString(),
+		pushOptions(r.clientManager.PushDefaults(), m.PreserveLayout, m.Archive),
+	)
+	if err != nil {
+		diags.AddError(
+			"Error updating Workspace",
+			fmt.Sprintf("Failed to push Workspace (id: %d) content with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	content, err := r.clientManager.GetWorkspace(ctx, id, key, secret, "")
+	if err != nil {
+		diags.AddError(
+			"Error retrieving Workspace",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %d) content after pushing with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	m.SourceChecksum, err = resolveSourceChecksum(m.Source, m.SourceChecksum)
+	if err != nil {
+		diags.AddError(
+			"Error computing Workspace source checksum",
+			fmt.Sprintf("Failed to compute the checksum of %s with error: %s", m.Source, err),
+		)
+		return
+	}
+
+	m.Name = types.StringValue(content.Name)
+	m.Description = types.StringValue(content.Description)
+	m.Revision = types.Int64Value(content.Revision)
+	m.RemoteRevision = m.Revision
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"github.com/fstaoe/terraform-provider-structurizr/internal/acctest"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/util"
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResourceWorkspaceContent_Basic(t *testing.T) {
+	content := &acctest.MockEndpoint{
+		Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+		Response: &acctest.MockResponse{
+			StatusCode:  http.StatusOK,
+			Body:        acctest.MockResourceWorkspaceWithSourceContent,
+			ContentType: "application/json",
+		},
+		Calls: 11,
+	}
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPut, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceUpdate,
+				ContentType: "application/json",
+			},
+			Calls: 3,
+		},
+		content,
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		CheckDestroy: func(state *terraform.State) error {
+			return acctest.AssertMockEndpointsCalls(endpoints)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccResourceWorkspaceContentConfig("ba47f1dae6946adbad62496b6dd6b7a3"),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace_content.test", "id", "1"),
+					resource.TestCheckResourceAttr("structurizr_workspace_content.test", "name", "Workspace DSL"),
+					resource.TestCheckResourceAttr("structurizr_workspace_content.test", "description", "Managed Workspace by DSL"),
+					resource.TestCheckResourceAttr("structurizr_workspace_content.test", "revision", "2"),
+				),
+			},
+			{
+				ResourceName:            "structurizr_workspace_content.test",
+				ImportState:             true,
+				ImportStateId:           "1:691e0542-5c4d-4f74-be4a-38134a0aa0bf:8497f68e-75b9-431b-b067-cf86a074205c",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"source", "source_checksum"},
+				ConfigVariables:         config.Variables{"host": config.StringVariable(mockServer.URL)},
+			},
+			{
+				Config:          testAccResourceWorkspaceContentConfig("1ff0a9d35b8b52b3e4b0a40c0a6ef2d1"),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check: resource.TestCheckResourceAttr(
+					"structurizr_workspace_content.test",
+					"source_checksum",
+					"1ff0a9d35b8b52b3e4b0a40c0a6ef2d1",
+				),
+			},
+			{
+				// The workspace is edited in the Structurizr UI, the refresh records the remote revision next to the
+				// pushed one and the drift is planned as an update.
+				PreConfig: func() {
+					content.Response.Body = strings.Replace(content.Response.Body, `"revision": 2`, `"revision": 3`, 1)
+				},
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace_content.test", "source_checksum", "1ff0a9d35b8b52b3e4b0a40c0a6ef2d1"),
+					resource.TestCheckResourceAttr("structurizr_workspace_content.test", "revision", "2"),
+					resource.TestCheckResourceAttr("structurizr_workspace_content.test", "remote_revision", "3"),
+				),
+			},
+			{
+				// Applying the drift pushes the source again
+				Config:          testAccResourceWorkspaceContentConfig("1ff0a9d35b8b52b3e4b0a40c0a6ef2d1"),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace_content.test", "revision", "3"),
+					resource.TestCheckResourceAttr("structurizr_workspace_content.test", "remote_revision", "3"),
+				),
+			},
+		},
+	})
+}
+
+// testAccResourceWorkspaceContentConfig configures the provider without admin API key, as for the cloud service
+func testAccResourceWorkspaceContentConfig(checksum string) string {
+	return util.ConfigCompose(`variable "host" {}
+provider "structurizr" {
+    host = var.host
+}
+`, `
+resource "structurizr_workspace_content" "test" {
+    id              = 1
+    api_key         = "691e0542-5c4d-4f74-be4a-38134a0aa0bf"
+    api_secret      = "8497f68e-75b9-431b-b067-cf86a074205c"
+    source          = "testdata/workspace.dsl"
+    source_checksum = "`+checksum+`"
+}
+`)
+}
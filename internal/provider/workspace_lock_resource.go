@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strconv"
+)
+
+const (
+	// defaultLockUser is the user recorded by Structurizr when a workspace is locked by Terraform
+	defaultLockUser = "terraform"
+	// defaultLockAgent is the agent recorded by Structurizr when a workspace is locked by Terraform
+	defaultLockAgent = "terraform-provider-structurizr"
+)
+
+// WorkspaceLockResourceModel represents a lock held on a workspace in the structurizr
+type WorkspaceLockResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	WorkspaceID types.Int64  `tfsdk:"workspace_id"`
+	APIKey      types.String `tfsdk:"api_key"`
+	APISecret   types.String `tfsdk:"api_secret"`
+	User        types.String `tfsdk:"user"`
+	Agent       types.String `tfsdk:"agent"`
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                     = &workspaceLockResource{}
+	_ resource.ResourceWithConfigure        = &workspaceLockResource{}
+	_ resource.ResourceWithConfigValidators = &workspaceLockResource{}
+)
+
+// NewWorkspaceLockResource is a helper function to simplify the provider implementation.
+func NewWorkspaceLockResource() resource.Resource {
+	return &workspaceLockResource{}
+}
+
+// workspaceLockResource is the resource implementation.
+type workspaceLockResource struct {
+	clientManager *client.Manager
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *workspaceLockResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	m, ok := req.ProviderData.(*client.Manager)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected resource Configure Type",
+			fmt.Sprintf(
+				"Expected *client.Manager, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	r.clientManager = m
+}
+
+// ConfigValidators returns a list of functions which will all be performed during validation.
+func (r *workspaceLockResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		// Validate the workspace credentials are either both null or both known values.
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("api_key"),
+			path.MatchRoot("api_secret"),
+		),
+	}
+}
+
+// Metadata returns the resource type name. It can be used to register other type of information.
+func (r *workspaceLockResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_lock"
+}
+
+// Schema defines the schema for the resource.
+func (r *workspaceLockResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Locks a Workspace for as long as the resource exists, so it cannot be modified by other users " +
+			"or agents (e.g. the Structurizr UI). The Workspace is unlocked when the resource is destroyed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				Description:   "The identifier of the lock, which is the identifier of the locked Workspace.",
+			},
+			"workspace_id": schema.Int64Attribute{
+				Required:      true,
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+				Description:   "The identifier of the Workspace to lock.",
+			},
+			"api_key": schema.StringAttribute{
+				Optional:      true,
+				Sensitive:     true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Description: "The API key specific to the Workspace. " +
+					"When omitted, it is looked up using the admin API key of the provider.",
+			},
+			"api_secret": schema.StringAttribute{
+				Optional:      true,
+				Sensitive:     true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Description: "The API secret key specific to the Workspace. " +
+					"When omitted, it is looked up using the admin API key of the provider.",
+			},
+			"user": schema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Default:       stringdefault.StaticString(defaultLockUser),
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Description:   fmt.Sprintf("The user holding the lock. Defaults to `%s`.", defaultLockUser),
+			},
+			"agent": schema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Default:       stringdefault.StaticString(defaultLockAgent),
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				Description:   fmt.Sprintf("The agent holding the lock. Defaults to `%s`.", defaultLockAgent),
+			},
+		},
+	}
+}
+
+// Create locks the workspace and sets the initial Terraform state.
+func (r *workspaceLockResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan WorkspaceLockResourceModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("[CREATE] Plan: %s", plan))
+
+	id := plan.WorkspaceID.ValueInt64()
+	key, secret, err := workspaceCredentials(ctx, r.clientManager, id, plan.APIKey, plan.APISecret)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("workspace_id"),
+			"Error retrieving Workspace credentials",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %d) credentials with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	_, err = r.clientManager.LockWorkspace(ctx, id, key, secret, plan.User.ValueString(), plan.Agent.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error locking Workspace",
+			fmt.Sprintf("Failed to lock Workspace (id: %d) with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.FormatInt(id, 10))
+
+	tflog.Trace(ctx, fmt.Sprintf("[CREATE] Storing Workspace lock: %+v", plan))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+// Structurizr does not expose the lock status of a workspace, therefore the state is kept as is.
+func (r *workspaceLockResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state WorkspaceLockResourceModel
+	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("[READ] State %s", state))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update is never called as every attribute requires the lock to be replaced.
+func (r *workspaceLockResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan WorkspaceLockResourceModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete unlocks the workspace and removes the Terraform state on success.
+func (r *workspaceLockResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state WorkspaceLockResourceModel
+	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("[DELETE] State %s", state))
+
+	id := state.WorkspaceID.ValueInt64()
+	key, secret, err := workspaceCredentials(ctx, r.clientManager, id, state.APIKey, state.APISecret)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error retrieving Workspace credentials",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %d) credentials with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	_, err = r.clientManager.UnlockWorkspace(ctx, id, key, secret, state.User.ValueString(), state.Agent.ValueString())
+	if errors.Is(err, model.APIErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Workspace (id: %d) not found on remote server, assuming it is already unlocked", id))
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error unlocking Workspace",
+			fmt.Sprintf("Failed to unlock Workspace (id: %d) with error: %s", id, errorDetail(err)),
+		)
+	}
+}
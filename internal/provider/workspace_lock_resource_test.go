@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"github.com/fstaoe/terraform-provider-structurizr/internal/acctest"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/util"
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"net/http"
+	"testing"
+)
+
+func TestResourceWorkspaceLock_Basic(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicGet,
+				ContentType: "application/json",
+			},
+			Calls: 2,
+		},
+		{
+			Request: &acctest.MockRequest{
+				Method: http.MethodPut,
+				Uri:    "/api/workspace/1/lock?agent=terraform-provider-structurizr&user=terraform",
+			},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceLock,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{
+				Method: http.MethodDelete,
+				Uri:    "/api/workspace/1/lock?agent=terraform-provider-structurizr&user=terraform",
+			},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceLock,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		CheckDestroy: func(state *terraform.State) error {
+			return acctest.AssertMockEndpointsCalls(endpoints)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccResourceWorkspaceLockConfigBasic(),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace_lock.test", "id", "1"),
+					resource.TestCheckResourceAttr("structurizr_workspace_lock.test", "workspace_id", "1"),
+					resource.TestCheckResourceAttr("structurizr_workspace_lock.test", "user", "terraform"),
+					resource.TestCheckResourceAttr("structurizr_workspace_lock.test", "agent", "terraform-provider-structurizr"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceWorkspaceLockConfigBasic() string {
+	return util.ConfigCompose(testAccProvider(), `
+resource "structurizr_workspace_lock" "test" {
+    workspace_id = 1
+}
+`)
+}
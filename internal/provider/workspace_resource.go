@@ -2,49 +2,83 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/fstaoe/terraform-provider-structurizr/internal/client"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api"
 	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/source"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"os"
 	"strconv"
-	"sync"
+	"strings"
 	"time"
 )
 
 // WorkspaceResourceModel represents a workspace in the structurizr
 type WorkspaceResourceModel struct {
-	ID               types.Int64  `tfsdk:"id"`
-	Name             types.String `tfsdk:"name"`
-	Description      types.String `tfsdk:"description"`
-	APIKey           types.String `tfsdk:"api_key"`
-	APISecret        types.String `tfsdk:"api_secret"`
-	PublicURL        types.String `tfsdk:"public_url"`
-	PrivateURL       types.String `tfsdk:"private_url"`
-	ShareableURL     types.String `tfsdk:"shareable_url"`
-	Source           types.String `tfsdk:"source"`
-	SourceChecksum   types.String `tfsdk:"source_checksum"`
-	SourcePassphrase types.String `tfsdk:"source_passphrase"`
-	LastUpdated      types.String `tfsdk:"last_updated"`
+	ID                 types.Int64    `tfsdk:"id"`
+	Name               types.String   `tfsdk:"name"`
+	Description        types.String   `tfsdk:"description"`
+	APIKey             types.String   `tfsdk:"api_key"`
+	APISecret          types.String   `tfsdk:"api_secret"`
+	PublicURL          types.String   `tfsdk:"public_url"`
+	PrivateURL         types.String   `tfsdk:"private_url"`
+	ShareableURL       types.String   `tfsdk:"shareable_url"`
+	Source             types.String   `tfsdk:"source"`
+	SourceContent      types.String   `tfsdk:"source_content"`
+	SourceFormat       types.String   `tfsdk:"source_format"`
+	SourceChecksum     types.String   `tfsdk:"source_checksum"`
+	SourcePassphrase   types.String   `tfsdk:"source_passphrase"`
+	PinnedVersion      types.String   `tfsdk:"pinned_version"`
+	PreserveLayout     types.Bool     `tfsdk:"preserve_layout"`
+	Archive            types.Bool     `tfsdk:"archive"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	ArchiveOnDestroy   types.String   `tfsdk:"archive_on_destroy"`
+	Revision           types.Int64    `tfsdk:"revision"`
+	RemoteRevision     types.Int64    `tfsdk:"remote_revision"`
+	LastModifiedDate   types.String   `tfsdk:"last_modified_date"`
+	LastModifiedUser   types.String   `tfsdk:"last_modified_user"`
+	LastModifiedAgent  types.String   `tfsdk:"last_modified_agent"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_     resource.Resource                     = &workspaceResource{}
-	_     resource.ResourceWithConfigure        = &workspaceResource{}
-	_     resource.ResourceWithImportState      = &workspaceResource{}
-	_     resource.ResourceWithConfigValidators = &workspaceResource{}
-	guard sync.Mutex
+	_ resource.Resource                     = &workspaceResource{}
+	_ resource.ResourceWithConfigure        = &workspaceResource{}
+	_ resource.ResourceWithImportState      = &workspaceResource{}
+	_ resource.ResourceWithConfigValidators = &workspaceResource{}
+	_ resource.ResourceWithModifyPlan       = &workspaceResource{}
+	_ resource.ResourceWithUpgradeState     = &workspaceResource{}
 )
 
+// Default timeouts of the operations on a workspace, overridable with the timeouts block
+const (
+	defaultWorkspaceCreateTimeout = 20 * time.Minute
+	defaultWorkspaceReadTimeout   = 5 * time.Minute
+	defaultWorkspaceUpdateTimeout = 20 * time.Minute
+	defaultWorkspaceDeleteTimeout = 5 * time.Minute
+)
+
+// errWorkspaceNotFound is returned when a workspace does not exist on the remote server
+var errWorkspaceNotFound = errors.New("workspace not found on remote server")
+
 // NewWorkspaceResource is a helper function to simplify the provider implementation.
 func NewWorkspaceResource() resource.Resource {
 	return &workspaceResource{}
@@ -79,10 +113,10 @@ func (r *workspaceResource) Configure(_ context.Context, req resource.ConfigureR
 // ConfigValidators returns a list of functions which will all be performed during validation.
 func (r *workspaceResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
 	return []resource.ConfigValidator{
-		// Validate the schema defined attributes are either both null or both known values.
-		resourcevalidator.RequiredTogether(
+		// A workspace is pushed either from a file or from an inline content
+		resourcevalidator.Conflicting(
 			path.MatchRoot("source"),
-			path.MatchRoot("source_checksum"),
+			path.MatchRoot("source_content"),
 		),
 	}
 }
@@ -93,8 +127,13 @@ func (r *workspaceResource) Metadata(_ context.Context, req resource.MetadataReq
 }
 
 // Schema defines the schema for the resource.
-func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *workspaceResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		// Version 1 replaced last_updated by the last_modified_* attributes
+		Version: 1,
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
+		},
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Computed:      true,
@@ -102,12 +141,32 @@ func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description:   "The identifier of the Workspace used to perform further operations.",
 			},
 			"name": schema.StringAttribute{
-				Computed:    true,
-				Description: "The name of the Workspace",
+				Optional: true,
+				Computed: true,
+				Description: "The name of the Workspace. It is planned from the header of the source when declared. " +
+					"Without a source, it is pushed in a generated Workspace, empty when created, which is renamed " +
+					"when it changes. Conflicts with `source`, `source_content` and `pinned_version`.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("source"),
+						path.MatchRoot("source_content"),
+						path.MatchRoot("pinned_version"),
+					),
+				},
 			},
 			"description": schema.StringAttribute{
-				Computed:    true,
-				Description: "The description of the Workspace explaining roughly what it is about.",
+				Optional: true,
+				Computed: true,
+				Description: "The description of the Workspace explaining roughly what it is about. It is planned from " +
+					"the header of the source when declared. Without a source, it is pushed in a generated Workspace " +
+					"like the `name`. Conflicts with `source`, `source_content` and `pinned_version`.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("source"),
+						path.MatchRoot("source_content"),
+						path.MatchRoot("pinned_version"),
+					),
+				},
 			},
 			"api_key": schema.StringAttribute{
 				Computed:      true,
@@ -154,42 +213,369 @@ func (r *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 					"If the value of this attribute is configured and removed, Terraform will destroy and recreate the resource.",
 				)},
 			},
+			"source_content": schema.StringAttribute{
+				Optional: true,
+				Description: "The DSL/JSON content representing a Workspace, e.g. rendered with `templatefile()`. It is " +
+					"staged in a private temporary file which is removed once pushed. Conflicts with `source`.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplaceIf(func(
+					ctx context.Context,
+					req planmodifier.StringRequest,
+					resp *stringplanmodifier.RequiresReplaceIfFuncResponse,
+				) {
+					if !req.ConfigValue.IsNull() || req.StateValue.IsNull() {
+						return
+					}
+
+					// Moving the content to a source file keeps the workspace
+					var file types.String
+					resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("source"), &file)...)
+
+					resp.RequiresReplace = file.IsNull()
+				},
+					"If the value of this attribute is configured and removed without a source, Terraform will destroy and recreate the resource.",
+					"If the value of this attribute is configured and removed without a `source`, Terraform will destroy and recreate the resource.",
+				)},
+			},
+			"source_format": schema.StringAttribute{
+				Optional: true,
+				Description: "The format of `source_content`, either `dsl` or `json`. It is detected from the content " +
+					"when omitted, JSON workspaces being objects.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(source.Formats...),
+					stringvalidator.AlsoRequires(path.MatchRoot("source_content")),
+				},
+			},
 			"source_checksum": schema.StringAttribute{
-				Optional:    true,
-				Description: "The checksum of the source file.",
+				Optional: true,
+				Computed: true,
+				Description: "The checksum of the source file, the source is pushed again when it changes. It defaults " +
+					"to the SHA-256 digest of the source and of every file it includes through the `!include`, `!docs` " +
+					"and `!adrs` directives, as returned by the `workspace_checksum` function.",
+				PlanModifiers: []planmodifier.String{sourceChecksum()},
+				Validators:    []validator.String{stringvalidator.AlsoRequires(path.MatchRoot("source"))},
 			},
 			"source_passphrase": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
 				Description: "The passphrase to use when the client-side encryption is enabled on the workspace.",
 			},
-			"last_updated": schema.StringAttribute{
+			"preserve_layout": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether the layout of the remote diagrams, such as the one made in the Structurizr UI, is " +
+					"merged into the pushed source. Defaults to the `preserve_layout` of the provider.",
+			},
+			"archive": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether the previous version of the Workspace is archived into the `archive_dir` of the " +
+					"provider before each push. Defaults to the `archive` of the provider.",
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional: true,
+				Description: "Whether the Workspace is protected from being destroyed. It must be removed or set to " +
+					"false, and applied, before the Workspace can be destroyed.",
+			},
+			"archive_on_destroy": schema.StringAttribute{
+				Optional: true,
+				Description: "The directory the Workspace is archived into before it is destroyed, as the JSON " +
+					"stored by the remote server, so encrypted content is kept encrypted. The Workspace is not " +
+					"destroyed when it cannot be archived.",
+				Validators: []validator.String{stringvalidator.LengthAtLeast(1)},
+			},
+			"pinned_version": schema.StringAttribute{
+				Optional: true,
+				Description: "The identifier of a previous version of the Workspace to restore, as listed by the " +
+					"`structurizr_workspace_versions` data source. While it is set, the Workspace content is restored " +
+					"from this version instead of being pushed from its source, a version which is not kept by the " +
+					"remote server fails the plan. Removing it pushes the source again.",
+			},
+			"revision": schema.Int64Attribute{
+				Computed: true,
+				Description: "The revision of the Workspace recorded after its content was pushed, incremented by the " +
+					"remote server on every change. When the `remote_revision` differs (e.g. the Workspace was edited " +
+					"in the Structurizr UI), the drift is shown in the plan and applying it pushes the content again, " +
+					"overwriting the out-of-band changes.",
+			},
+			"remote_revision": schema.Int64Attribute{
 				Computed:    true,
-				Description: "It provides the information when the Workspace was last updated.",
+				Description: "The latest revision of the Workspace on the remote server, refreshed on every read.",
+			},
+			"last_modified_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "The date when the Workspace was last modified, as recorded by the remote server.",
+			},
+			"last_modified_user": schema.StringAttribute{
+				Computed:    true,
+				Description: "The user who last modified the Workspace, as recorded by the remote server.",
+			},
+			"last_modified_agent": schema.StringAttribute{
+				Computed: true,
+				Description: "The agent (e.g. structurizr-cli, structurizr-web) which last modified the Workspace, as " +
+					"recorded by the remote server.",
 			},
 		},
 	}
 }
 
+// UpgradeState migrates the state stored by the previous versions of the schema.
+func (r *workspaceResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// The last_modified_* attributes replace last_updated, they are known after the next refresh
+		0: {StateUpgrader: upgradeWorkspaceStateV0},
+	}
+}
+
+// upgradeWorkspaceStateV0 migrates the state of version 0 to version 1 from its raw JSON, so the schema of version 0
+// does not have to be kept
+func upgradeWorkspaceStateV0(_ context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var state map[string]json.RawMessage
+	if err := json.Unmarshal(req.RawState.JSON, &state); err != nil {
+		resp.Diagnostics.AddError(
+			"Error upgrading Workspace state",
+			fmt.Sprintf("Failed to parse the Workspace state of version 0 with error: %s", err),
+		)
+		return
+	}
+
+	delete(state, "last_updated")
+	for _, attr := range []string{"last_modified_date", "last_modified_user", "last_modified_agent"} {
+		state[attr] = json.RawMessage("null")
+	}
+
+	upgraded, err := json.Marshal(state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error upgrading Workspace state",
+			fmt.Sprintf("Failed to encode the Workspace state of version 1 with error: %s", err),
+		)
+		return
+	}
+
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+}
+
+// ModifyPlan plans the name and the description declared by the source of the workspace when it is planned to be
+// pushed, and validates and inspects the source, so an invalid source fails the plan rather than the apply and the
+// findings of the inspection are shown as warnings.
+func (r *workspaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is pushed when the workspace is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan WorkspaceResourceModel
+	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	name, description, diags := configuredHeader(ctx, req.Config)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state WorkspaceResourceModel
+		if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+			return
+		}
+
+		// The name and the description only change when the content is pushed again, or out of band in which case
+		// they have already been refreshed. Unchanged sources are not validated again as running the CLI takes a while.
+		// A content modified outside of Terraform is pushed again, overwriting the out-of-band changes.
+		if !contentChanged(state, plan) && !drifted(state) && !renamed(state, name, description) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name"), state.Name)...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("description"), state.Description)...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("revision"), state.Revision)...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("remote_revision"), state.RemoteRevision)...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("last_modified_date"), state.LastModifiedDate)...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("last_modified_user"), state.LastModifiedUser)...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("last_modified_agent"), state.LastModifiedAgent)...)
+			return
+		}
+
+		// A change of the checksum alone is planned by its plan modifier, after Terraform marked the computed
+		// attributes unknown, so the attributes recorded when the content is pushed are marked unknown here.
+		// Renaming a generated workspace keeps the attribute which is not configured.
+		switch {
+		case name.IsNull() && description.IsNull():
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name"), types.StringUnknown())...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("description"), types.StringUnknown())...)
+		case name.IsNull():
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name"), state.Name)...)
+		case description.IsNull():
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("description"), state.Description)...)
+		}
+		for _, attr := range []string{"last_modified_date", "last_modified_user", "last_modified_agent"} {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attr), types.StringUnknown())...)
+		}
+		for _, attr := range []string{"revision", "remote_revision"} {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attr), types.Int64Unknown())...)
+		}
+	}
+
+	// A pinned version is restored instead of the source, and unknown sources are only known at apply time
+	if plan.PinnedVersion.ValueString() != "" {
+		r.validatePinnedVersion(ctx, plan, &resp.Diagnostics)
+		return
+	}
+	if plan.Source.IsUnknown() || plan.SourceContent.IsUnknown() ||
+		plan.SourceChecksum.IsUnknown() || !hasContent(plan) {
+		return
+	}
+
+	content := []byte(plan.SourceContent.ValueString())
+	if plan.SourceContent.IsNull() {
+		// A source which cannot be read is reported by its validation, or when it is pushed
+		content, _ = os.ReadFile(plan.Source.ValueString())
+	}
+
+	header := source.ReadHeader(content)
+	if header.HasName {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name"), header.Name)...)
+	}
+	if header.HasDescription {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("description"), header.Description)...)
+	}
+
+	r.validateSource(ctx, plan, &resp.Diagnostics)
+}
+
+// validatePinnedVersion checks the pinned version of an existing workspace is kept by the remote server, so a version
+// which does not exist fails the plan rather than the apply
+func (r *workspaceResource) validatePinnedVersion(ctx context.Context, plan WorkspaceResourceModel, diags *diag.Diagnostics) {
+	// Nothing is checked before the provider is configured, or before the workspace is created
+	if r.clientManager == nil || plan.ID.IsUnknown() || plan.ID.IsNull() {
+		return
+	}
+
+	id := plan.ID.ValueInt64()
+	key, secret, err := workspaceCredentials(ctx, r.clientManager, id, plan.APIKey, plan.APISecret)
+	if err != nil {
+		diags.AddError(
+			"Error reading Workspace versions",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %d) credentials with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	res, err := r.clientManager.GetWorkspaceVersions(ctx, id, key, secret)
+	if err != nil {
+		diags.AddError(
+			"Error reading Workspace versions",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %d) versions with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	version := plan.PinnedVersion.ValueString()
+	if res.FindByID(version) != nil {
+		return
+	}
+
+	available := make([]string, 0, len(res.Versions))
+	for _, v := range res.Versions {
+		available = append(available, v.VersionID)
+	}
+
+	detail := "The remote server keeps no previous versions of the Workspace."
+	if len(available) > 0 {
+		detail = fmt.Sprintf("The available versions, latest first, are: %s.", strings.Join(available, ", "))
+	}
+
+	diags.AddAttributeError(
+		path.Root("pinned_version"),
+		"Unknown Workspace version",
+		fmt.Sprintf("The version %q of the Workspace (id: %d) is not kept by the remote server. %s", version, id, detail),
+	)
+}
+
+// validateSource validates and inspects the planned source of a workspace with the Structurizr CLI, unless the
+// validation is disabled or not supported by the push client
+func (r *workspaceResource) validateSource(ctx context.Context, plan WorkspaceResourceModel, diags *diag.Diagnostics) {
+	// Nothing is validated before the provider is configured
+	if r.clientManager == nil {
+		return
+	}
+
+	validator, ok := r.clientManager.Validator()
+	if !ok {
+		return
+	}
+
+	attr, name := path.Root("source"), plan.Source.ValueString()
+	if content := plan.SourceContent.ValueString(); content != "" {
+		staged, cleanup, err := source.Stage(content, source.Format(plan.SourceFormat.ValueString()))
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("source_content"),
+				"Error validating Workspace source",
+				fmt.Sprintf("Failed to stage the source content with error: %s", err),
+			)
+			return
+		}
+		defer cleanup()
+
+		attr, name = path.Root("source_content"), staged
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Validating Workspace source %s", name))
+
+	if err := validator.ValidateWorkspace(ctx, name); err != nil {
+		diags.AddAttributeError(
+			attr,
+			"Invalid Workspace source",
+			fmt.Sprintf(
+				"The Workspace source is rejected by the Structurizr CLI: %s\n\n"+
+					"Fix the source, or set validate_sources to false in the provider configuration "+
+					"when it cannot be validated where Terraform plans.",
+				err,
+			),
+		)
+		return
+	}
+
+	findings, err := validator.InspectWorkspace(ctx, name)
+	if err != nil {
+		diags.AddAttributeWarning(
+			attr,
+			"Unable to inspect Workspace source",
+			fmt.Sprintf("Failed to inspect the Workspace source with error: %s", err),
+		)
+		return
+	}
+
+	for _, f := range findings {
+		diags.AddAttributeWarning(attr, fmt.Sprintf("Workspace source inspection %s", f.Severity), f.Message)
+	}
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *workspaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	// Preventing race conditions when running Terraform with multiple resources
-	guard.Lock()
-	defer guard.Unlock()
-
 	// Retrieve values from plan
 	var state, plan WorkspaceResourceModel
 	if resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...); resp.Diagnostics.HasError() {
 		return
 	}
 
+	timeout, diags := plan.Timeouts.Create(ctx, defaultWorkspaceCreateTimeout)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	name, description, diags := configuredHeader(ctx, req.Config)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Trace(ctx, fmt.Sprintf("[CREATE] State: %s Plan: %s", state, plan))
 
 	workspace, err := r.clientManager.CreateWorkspace(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating Workspace",
-			fmt.Sprintf("Failed to create Workspace with error: %s", err),
+			fmt.Sprintf("Failed to create Workspace with error: %s", errorDetail(err)),
 		)
 		return
 	}
@@ -204,27 +590,24 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 	state.PublicURL = types.StringValue(workspace.PublicURL)
 	state.PrivateURL = types.StringValue(workspace.PrivateURL)
 	state.ShareableURL = types.StringValue(workspace.ShareableURL)
-	state.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	state.PreserveLayout = plan.PreserveLayout
+	state.Archive = plan.Archive
+	state.DeletionProtection = plan.DeletionProtection
+	state.ArchiveOnDestroy = plan.ArchiveOnDestroy
+	state.Timeouts = plan.Timeouts
 
 	tflog.Trace(ctx, fmt.Sprintf("[CREATE] After Setting Workspace %+v with State: %s Plan: %s", workspace, state, plan))
 
-	// The workspace will be updated on the remote server using it source when provided
-	// and the state will be as well refreshed using the latest data from the remote server
-	if plan.Source.ValueString() != "" {
+	// The workspace will be updated on the remote server using it source when provided, or its configured name and
+	// description, and the state will be as well refreshed using the latest data from the remote server
+	if hasContent(plan) || !name.IsNull() || !description.IsNull() {
 		tflog.Trace(ctx, fmt.Sprintf("[CREATE] Updating Workspace %+v with State: %s Plan: %s", workspace, state, plan))
 
-		err = r.clientManager.PushWorkspace(
-			ctx,
-			workspace.ID,
-			workspace.APIKey,
-			workspace.APISecret,
-			plan.SourcePassphrase.ValueString(),
-			plan.Source.ValueString(),
-		)
+		err = r.pushWorkspace(ctx, plan, workspace.ID, workspace.APIKey, workspace.APISecret)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error updating Workspace",
-				fmt.Sprintf("Failed to update Workspace (id: %d) with error: %s", workspace.ID, err),
+				fmt.Sprintf("Failed to update Workspace (id: %d) with error: %s", workspace.ID, errorDetail(err)),
 			)
 
 			tflog.Trace(ctx, fmt.Sprintf("[CREATE] Rolling back Workspace %+v with State: %s Plan: %s", workspace, state, plan))
@@ -233,7 +616,7 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 			if _, err = r.clientManager.DeleteWorkspace(ctx, workspace.ID); err != nil {
 				resp.Diagnostics.AddError(
 					"Error rolling back Workspace",
-					fmt.Sprintf("Failed to rollback Workspace (id: %d) creation with error: %s", workspace.ID, err),
+					fmt.Sprintf("Failed to rollback Workspace (id: %d) creation with error: %s", workspace.ID, errorDetail(err)),
 				)
 			}
 			return
@@ -245,7 +628,7 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error retrieving Workspace",
-				fmt.Sprintf("Failed to retrieve Workspace (id: %d) after updating with error: %s", workspace.ID, err),
+				fmt.Sprintf("Failed to retrieve Workspace (id: %d) after updating with error: %s", workspace.ID, errorDetail(err)),
 			)
 			return
 		}
@@ -255,10 +638,30 @@ func (r *workspaceResource) Create(ctx context.Context, req resource.CreateReque
 		state.Name = types.StringValue(updatedWorkspace.Name)
 		state.Description = types.StringValue(updatedWorkspace.Description)
 		state.Source = plan.Source
-		state.SourceChecksum = plan.SourceChecksum
+		state.SourceContent = plan.SourceContent
+		state.SourceFormat = plan.SourceFormat
+		state.SourceChecksum, err = resolveSourceChecksum(plan.Source, plan.SourceChecksum)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error computing Workspace source checksum",
+				fmt.Sprintf("Failed to compute the checksum of %s with error: %s", plan.Source, err),
+			)
+			return
+		}
 		state.SourcePassphrase = plan.SourcePassphrase
+		state.PinnedVersion = plan.PinnedVersion
 	}
 
+	content, err := r.getWorkspaceContent(ctx, workspace.ID, workspace.APIKey, workspace.APISecret)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error retrieving Workspace",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %d) revision after creating with error: %s", workspace.ID, errorDetail(err)),
+		)
+		return
+	}
+	setLastModified(&state, content)
+
 	tflog.Trace(ctx, fmt.Sprintf("[CREATE] Storing Workspace State: %+v", state))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
@@ -271,13 +674,28 @@ func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	timeout, diags := state.Timeouts.Read(ctx, defaultWorkspaceReadTimeout)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	tflog.Trace(ctx, fmt.Sprintf("[READ] State %s", state))
 
 	workspace, err := r.getWorkspaceByID(ctx, state.ID.ValueInt64())
+	if errors.Is(err, errWorkspaceNotFound) {
+		// The workspace has been deleted outside of Terraform, removing it from the state lets Terraform plan its re-creation
+		tflog.Warn(ctx, fmt.Sprintf("Workspace (id: %s) not found on remote server, removing it from the state", state.ID))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving Workspace",
-			fmt.Sprintf("Failed to retrieve Workspace (id: %s) with error: %s", state.ID, err),
+			fmt.Sprintf("Failed to retrieve Workspace (id: %s) with error: %s", state.ID, errorDetail(err)),
 		)
 		return
 	}
@@ -291,7 +709,34 @@ func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest,
 	state.PublicURL = types.StringValue(workspace.PublicURL)
 	state.PrivateURL = types.StringValue(workspace.PrivateURL)
 	state.ShareableURL = types.StringValue(workspace.ShareableURL)
-	state.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+
+	content, err := r.getWorkspaceContent(ctx, workspace.ID, workspace.APIKey, workspace.APISecret)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error retrieving Workspace",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %s) revision with error: %s", state.ID, errorDetail(err)),
+		)
+		return
+	}
+
+	// The revision recorded after the last push of a workspace managed from a source is kept, so a remote revision
+	// which differs is planned by ModifyPlan as a push of its content
+	pushed := state.Revision
+	setLastModified(&state, content)
+
+	if hasContent(state) && !pushed.IsNull() {
+		state.Revision = pushed
+
+		if drifted(state) {
+			tflog.Warn(ctx, fmt.Sprintf(
+				"Workspace (id: %s) was modified outside of Terraform (revision %s, remote revision %s), "+
+					"its content will be pushed again",
+				state.ID,
+				state.Revision,
+				state.RemoteRevision,
+			))
+		}
+	}
 
 	tflog.Trace(ctx, fmt.Sprintf("[READ] Storing Workspace: %+v", state))
 
@@ -305,20 +750,46 @@ func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
+	timeout, diags := plan.Timeouts.Update(ctx, defaultWorkspaceUpdateTimeout)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	tflog.Trace(ctx, fmt.Sprintf("[UPDATE] Plan %s", plan))
 
-	err := r.clientManager.PushWorkspace(
-		ctx,
-		plan.ID.ValueInt64(),
-		plan.APIKey.ValueString(),
-		plan.APISecret.ValueString(),
-		plan.SourcePassphrase.ValueString(),
-		plan.Source.ValueString(),
-	)
+	name, description, diags := configuredHeader(ctx, req.Config)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state WorkspaceResourceModel
+	if resp.Diagnostics.Append(req.State.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the settings of the resource changed, such as the options of the next push, its timeouts or its
+	// protection, which are stored without pushing the content as planned by ModifyPlan
+	if !contentChanged(state, plan) && !drifted(state) && !renamed(state, name, description) {
+		tflog.Trace(ctx, fmt.Sprintf("[UPDATE] Storing Workspace settings: %+v", plan))
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		return
+	}
+
+	var err error
+	switch {
+	case hasContent(plan):
+		err = r.pushWorkspace(ctx, plan, plan.ID.ValueInt64(), plan.APIKey.ValueString(), plan.APISecret.ValueString())
+	case renamed(state, name, description):
+		err = r.renameWorkspace(ctx, plan, plan.ID.ValueInt64(), plan.APIKey.ValueString(), plan.APISecret.ValueString())
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error updating Workspace",
-			fmt.Sprintf("Failed to update Workspace (id: %s) with error: %s", plan.ID, err),
+			fmt.Sprintf("Failed to update Workspace (id: %s) with error: %s", plan.ID, errorDetail(err)),
 		)
 		return
 	}
@@ -327,16 +798,34 @@ func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateReque
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error retrieving Workspace",
-			fmt.Sprintf("Failed to retrieve Workspace (id: %s) after updating with error: %s", plan.ID, err),
+			fmt.Sprintf("Failed to retrieve Workspace (id: %s) after updating with error: %s", plan.ID, errorDetail(err)),
 		)
 		return
 	}
 
 	tflog.Trace(ctx, fmt.Sprintf("[UPDATE] Setting Workspace %+v to state %s", workspace, plan))
 
+	content, err := r.getWorkspaceContent(ctx, plan.ID.ValueInt64(), plan.APIKey.ValueString(), plan.APISecret.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error retrieving Workspace",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %s) revision after updating with error: %s", plan.ID, errorDetail(err)),
+		)
+		return
+	}
+
 	plan.Name = types.StringValue(workspace.Name)
 	plan.Description = types.StringValue(workspace.Description)
-	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	setLastModified(&plan, content)
+
+	plan.SourceChecksum, err = resolveSourceChecksum(plan.Source, plan.SourceChecksum)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error computing Workspace source checksum",
+			fmt.Sprintf("Failed to compute the checksum of %s with error: %s", plan.Source, err),
+		)
+		return
+	}
 
 	tflog.Trace(ctx, fmt.Sprintf("[UPDATE] Storing Workspace: %+v", plan))
 
@@ -350,53 +839,347 @@ func (r *workspaceResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
+	timeout, diags := state.Timeouts.Delete(ctx, defaultWorkspaceDeleteTimeout)
+	if resp.Diagnostics.Append(diags...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	tflog.Trace(ctx, fmt.Sprintf("[DELETE] State %s", state))
 
-	if _, err := r.clientManager.DeleteWorkspace(ctx, state.ID.ValueInt64()); err != nil {
+	if state.DeletionProtection.ValueBool() {
 		resp.Diagnostics.AddError(
-			"Error deleting Workspace",
-			fmt.Sprintf("Failed to delete Workspace (id: %s) after updating with error: %s", state.ID, err),
+			"Workspace is protected from deletion",
+			fmt.Sprintf(
+				"Workspace (id: %s) cannot be destroyed while deletion_protection is enabled. Remove it or set it to "+
+					"false, and apply, before destroying the Workspace.",
+				state.ID,
+			),
 		)
+		return
 	}
-}
 
-// ImportState imports and store the current resource state from the remote server. This is ideal when migrating to Terraform.
-func (r *workspaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	attrID := path.Root("id")
-	if attrID.Equal(path.Empty()) {
-		resp.Diagnostics.AddError(
-			"Error importing Workspace",
-			"Failed to import Workspace due to missing or invalid resource path",
-		)
+	if dir := state.ArchiveOnDestroy.ValueString(); dir != "" {
+		err := r.archiveWorkspace(ctx, state, dir)
+		if errors.Is(err, model.APIErrNotFound) {
+			tflog.Warn(ctx, fmt.Sprintf("Workspace (id: %s) not found on remote server, there is nothing to archive", state.ID))
+			return
+		}
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error archiving Workspace",
+				fmt.Sprintf(
+					"Failed to archive Workspace (id: %s) before deleting it with error: %s. The Workspace is not deleted.",
+					state.ID,
+					errorDetail(err),
+				),
+			)
+			return
+		}
+	}
+
+	_, err := r.clientManager.DeleteWorkspace(ctx, state.ID.ValueInt64())
+	if errors.Is(err, model.APIErrNotFound) {
+		tflog.Warn(ctx, fmt.Sprintf("Workspace (id: %s) not found on remote server, assuming it is already deleted", state.ID))
 		return
 	}
 
-	id, err := strconv.Atoi(req.ID)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error parsing Workspace ID",
-			fmt.Sprintf("Failed to parse Workspace (id: %s) with error: %s", req.ID, err),
+			"Error deleting Workspace",
+			fmt.Sprintf("Failed to delete Workspace (id: %s) after updating with error: %s", state.ID, errorDetail(err)),
 		)
-		return
 	}
+}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attrID, id)...)
+// ImportState imports and store the current resource state from the remote server. This is ideal when migrating to Terraform.
+// The workspace is identified by its ID, optionally followed by the passphrase of its client-side encryption as
+// <id>:<passphrase>, or by its name as name:<name>. It is checked to exist, and to be decrypted by the passphrase.
+func (r *workspaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var workspace *model.Workspace
+	var passphrase string
+
+	if name, ok := strings.CutPrefix(req.ID, "name:"); ok {
+		res, err := r.clientManager.GetWorkspaces(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error importing Workspace",
+				fmt.Sprintf("Failed to list workspaces with error: %s", errorDetail(err)),
+			)
+			return
+		}
+
+		matches := res.FindByName(name)
+		switch len(matches) {
+		case 0:
+			resp.Diagnostics.AddError(
+				"Error importing Workspace",
+				fmt.Sprintf("No Workspace named %q exists on the remote server", name),
+			)
+			return
+		case 1:
+			workspace = matches[0]
+		default:
+			ids := make([]string, 0, len(matches))
+			for _, m := range matches {
+				ids = append(ids, strconv.FormatInt(m.ID, 10))
+			}
+
+			resp.Diagnostics.AddError(
+				"Error importing Workspace",
+				fmt.Sprintf(
+					"%d Workspaces named %q exist on the remote server (ids: %s), import one of them by its ID",
+					len(matches),
+					name,
+					strings.Join(ids, ", "),
+				),
+			)
+			return
+		}
+	} else {
+		parts := strings.SplitN(req.ID, ":", 2)
+		id, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error parsing Workspace ID",
+				fmt.Sprintf(
+					"Expected an identifier of the form <id>, <id>:<passphrase> or name:<name>, got: %q",
+					req.ID,
+				),
+			)
+			return
+		}
+
+		if len(parts) == 2 {
+			passphrase = parts[1]
+		}
+
+		workspace, err = r.getWorkspaceByID(ctx, id)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error importing Workspace",
+				fmt.Sprintf("Failed to retrieve Workspace (id: %d) with error: %s", id, errorDetail(err)),
+			)
+			return
+		}
+	}
+
+	if passphrase != "" {
+		content, err := r.clientManager.GetWorkspace(ctx, workspace.ID, workspace.APIKey, workspace.APISecret, passphrase)
+		if err == nil && !content.IsEncrypted() {
+			err = errors.New("the workspace is not encrypted")
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error importing Workspace",
+				fmt.Sprintf("Failed to decrypt Workspace (id: %d) with the passphrase: %s", workspace.ID, errorDetail(err)),
+			)
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("source_passphrase"), passphrase)...)
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), workspace.ID)...)
 }
 
+// getWorkspaceByID looks up a workspace from the list of workspaces, errWorkspaceNotFound is returned when it is missing
 func (r *workspaceResource) getWorkspaceByID(ctx context.Context, id int64) (*model.Workspace, error) {
 	res, err := r.clientManager.GetWorkspaces(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list workspaces with error: %s", err)
-	}
-
-	if len(res.Workspaces) == 0 {
-		return nil, errors.New("workspaces not found on remote server")
+		return nil, fmt.Errorf("failed to list workspaces with error: %w", err)
 	}
 
 	workspace := res.FindByID(id)
 	if workspace == nil {
-		return nil, errors.New("workspace not found")
+		return nil, errWorkspaceNotFound
 	}
 
 	return workspace, nil
 }
+
+// getWorkspaceContent retrieves the current content of a workspace for its revision and last modification, encrypted
+// workspaces do not need to be decrypted
+func (r *workspaceResource) getWorkspaceContent(
+	ctx context.Context,
+	id int64,
+	key string,
+	secret string,
+) (*model.WorkspaceContent, error) {
+	return r.clientManager.GetWorkspace(ctx, id, key, secret, "")
+}
+
+// setLastModified records the revision and the last modification of the content of a workspace in the model
+func setLastModified(m *WorkspaceResourceModel, content *model.WorkspaceContent) {
+	m.Revision = types.Int64Value(content.Revision)
+	m.RemoteRevision = types.Int64Value(content.Revision)
+	m.LastModifiedDate = types.StringValue(content.LastModifiedDate)
+	m.LastModifiedUser = types.StringValue(content.LastModifiedUser)
+	m.LastModifiedAgent = types.StringValue(content.LastModifiedAgent)
+}
+
+// archiveWorkspace writes the content of a workspace into an archive of dir, as stored by the remote server
+func (r *workspaceResource) archiveWorkspace(ctx context.Context, state WorkspaceResourceModel, dir string) error {
+	id := state.ID.ValueInt64()
+
+	content, err := r.clientManager.GetWorkspace(ctx, id, state.APIKey.ValueString(), state.APISecret.ValueString(), "")
+	if err != nil {
+		return err
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Archiving Workspace (id: %d) into %s", id, dir))
+
+	return api.WriteArchive(dir, id, content.Raw)
+}
+
+// pushWorkspace updates the content of a workspace on the remote server, either by restoring its pinned version
+// or by pushing its source
+func (r *workspaceResource) pushWorkspace(
+	ctx context.Context,
+	plan WorkspaceResourceModel,
+	id int64,
+	key string,
+	secret string,
+) error {
+	if version := plan.PinnedVersion.ValueString(); version != "" {
+		tflog.Info(ctx, fmt.Sprintf("Restoring Workspace (id: %d) version %s", id, version))
+		return r.clientManager.RestoreWorkspaceVersion(ctx, id, key, secret, version)
+	}
+
+	options := pushOptions(r.clientManager.PushDefaults(), plan.PreserveLayout, plan.Archive)
+
+	if content := plan.SourceContent.ValueString(); content != "" {
+		name, cleanup, err := source.Stage(content, source.Format(plan.SourceFormat.ValueString()))
+		if err != nil {
+			return fmt.Errorf("failed to stage the source content with error: %w", err)
+		}
+		defer cleanup()
+
+		return r.clientManager.PushWorkspace(ctx, id, key, secret, plan.SourcePassphrase.ValueString(), name, options)
+	}
+
+	// A workspace without a source is generated from its name and description, empty as it has just been created
+	if plan.Source.IsNull() {
+		return r.pushHeader(ctx, plan, id, key, secret, nil)
+	}
+
+	return r.clientManager.PushWorkspace(
+		ctx,
+		id,
+		key,
+		secret,
+		plan.SourcePassphrase.ValueString(),
+		plan.Source.ValueString(),
+		options,
+	)
+}
+
+// renameWorkspace pushes the name and the description of a generated workspace, its current content is kept
+func (r *workspaceResource) renameWorkspace(
+	ctx context.Context,
+	plan WorkspaceResourceModel,
+	id int64,
+	key string,
+	secret string,
+) error {
+	current, err := r.clientManager.GetWorkspace(ctx, id, key, secret, plan.SourcePassphrase.ValueString())
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the current content with error: %w", err)
+	}
+
+	return r.pushHeader(ctx, plan, id, key, secret, current.Raw)
+}
+
+// pushHeader pushes a JSON workspace generated from the planned name and description of a workspace, on top of the
+// base JSON workspace when given
+func (r *workspaceResource) pushHeader(
+	ctx context.Context,
+	plan WorkspaceResourceModel,
+	id int64,
+	key string,
+	secret string,
+	base []byte,
+) error {
+	header := source.Header{
+		Name:           plan.Name.ValueString(),
+		Description:    plan.Description.ValueString(),
+		HasName:        !plan.Name.IsUnknown() && !plan.Name.IsNull(),
+		HasDescription: !plan.Description.IsUnknown() && !plan.Description.IsNull(),
+	}
+
+	content, err := source.Generate(header, base)
+	if err != nil {
+		return err
+	}
+
+	name, cleanup, err := source.Stage(string(content), source.FormatJSON)
+	if err != nil {
+		return fmt.Errorf("failed to stage the generated workspace with error: %w", err)
+	}
+	defer cleanup()
+
+	// There is no layout to merge into a generated workspace, a renamed one already carries its own
+	options := pushOptions(r.clientManager.PushDefaults(), plan.PreserveLayout, plan.Archive)
+	options.Merge = false
+
+	tflog.Info(ctx, fmt.Sprintf("Pushing generated Workspace (id: %d)", id))
+
+	return r.clientManager.PushWorkspace(ctx, id, key, secret, plan.SourcePassphrase.ValueString(), name, options)
+}
+
+// contentChanged reports whether the content of a workspace is planned to be pushed again, from another source,
+// another inline content, another pinned version or encrypted with another passphrase
+func contentChanged(state WorkspaceResourceModel, plan WorkspaceResourceModel) bool {
+	return !state.Source.Equal(plan.Source) || !state.SourceChecksum.Equal(plan.SourceChecksum) ||
+		!state.SourceContent.Equal(plan.SourceContent) || !state.SourceFormat.Equal(plan.SourceFormat) ||
+		!state.PinnedVersion.Equal(plan.PinnedVersion) || !state.SourcePassphrase.Equal(plan.SourcePassphrase)
+}
+
+// drifted reports whether the content of a workspace managed from a source was modified outside of Terraform since
+// it was last pushed
+func drifted(m WorkspaceResourceModel) bool {
+	return hasContent(m) && revisionDrifted(m.Revision, m.RemoteRevision)
+}
+
+// revisionDrifted reports whether the remote revision of a workspace differs from the revision recorded after its
+// content was last pushed, unknown until both are recorded
+func revisionDrifted(revision types.Int64, remote types.Int64) bool {
+	return !revision.IsNull() && !remote.IsNull() && revision.ValueInt64() != remote.ValueInt64()
+}
+
+// configuredHeader returns the name and the description configured for a workspace, which are only configured for
+// a workspace generated without a source
+func configuredHeader(ctx context.Context, config tfsdk.Config) (types.String, types.String, diag.Diagnostics) {
+	var name, description types.String
+	diags := config.GetAttribute(ctx, path.Root("name"), &name)
+	diags.Append(config.GetAttribute(ctx, path.Root("description"), &description)...)
+
+	return name, description, diags
+}
+
+// renamed reports whether the configured name or description of a generated workspace differs from its state
+func renamed(state WorkspaceResourceModel, name types.String, description types.String) bool {
+	return (!name.IsNull() && !name.Equal(state.Name)) || (!description.IsNull() && !description.Equal(state.Description))
+}
+
+// hasContent reports whether the content of a workspace is managed by Terraform, from a source, an inline content
+// or a pinned version
+func hasContent(m WorkspaceResourceModel) bool {
+	return m.Source.ValueString() != "" || m.SourceContent.ValueString() != "" || m.PinnedVersion.ValueString() != ""
+}
+
+// pushOptions returns the options of a push, the settings of the resource override the defaults of the provider
+func pushOptions(defaults api.PushOptions, preserveLayout types.Bool, archive types.Bool) api.PushOptions {
+	options := defaults
+	if !preserveLayout.IsNull() {
+		options.Merge = preserveLayout.ValueBool()
+	}
+	if !archive.IsNull() {
+		options.Archive = archive.ValueBool()
+	}
+
+	return options
+}
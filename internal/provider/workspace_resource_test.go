@@ -1,12 +1,31 @@
 package provider
 
 import (
+	"context"
+	"fmt"
 	"github.com/fstaoe/terraform-provider-structurizr/internal/acctest"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client/api/model"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/source"
 	"github.com/fstaoe/terraform-provider-structurizr/internal/util"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/config"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -30,6 +49,15 @@ func TestResourceWorkspace_Basic(t *testing.T) {
 			},
 			Calls: 1,
 		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicContent,
+				ContentType: "application/json",
+			},
+			Calls: 2,
+		},
 		{
 			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
 			Response: &acctest.MockResponse{
@@ -62,7 +90,7 @@ func TestResourceWorkspace_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "private_url", "/workspace/1"),
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "public_url", "/share/1"),
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "shareable_url", ""),
-					resource.TestCheckResourceAttrSet("structurizr_workspace.test", "last_updated"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "last_modified_date", "2024-05-01T09:00:00Z"),
 				),
 			},
 		},
@@ -87,7 +115,16 @@ func TestResourceWorkspace_Import(t *testing.T) {
 				Body:        acctest.MockResourceWorkspaceBasicGet,
 				ContentType: "application/json",
 			},
-			Calls: 2,
+			Calls: 3,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicContent,
+				ContentType: "application/json",
+			},
+			Calls: 4,
 		},
 		{
 			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
@@ -121,7 +158,7 @@ func TestResourceWorkspace_Import(t *testing.T) {
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "private_url", "/workspace/1"),
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "public_url", "/share/1"),
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "shareable_url", ""),
-					resource.TestCheckResourceAttrSet("structurizr_workspace.test", "last_updated"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "last_modified_date", "2024-05-01T09:00:00Z"),
 				),
 			},
 			{
@@ -132,12 +169,340 @@ func TestResourceWorkspace_Import(t *testing.T) {
 				ImportStateVerify: true,
 				// The below attributes does not exist in the Structurizr API, therefore there is no value for it
 				// during import.
-				ImportStateVerifyIgnore: []string{"source", "source_checksum", "last_updated"},
+				ImportStateVerifyIgnore: []string{"source", "source_checksum"},
+			},
+			{
+				ConfigVariables:         config.Variables{"host": config.StringVariable(mockServer.URL)},
+				ResourceName:            "structurizr_workspace.test",
+				ImportStateId:           "name:Workspace 0001",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"source", "source_checksum"},
+			},
+		},
+	})
+}
+
+func TestResourceWorkspace_ImportErrors(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceDuplicatesGet,
+				ContentType: "application/json",
+			},
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockDataSourceWorkspaceContentEncrypted,
+				ContentType: "application/json",
+			},
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/2"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockDataSourceWorkspaceContentBasic,
+				ContentType: "application/json",
+			},
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	step := func(id string, expectError string) resource.TestStep {
+		return resource.TestStep{
+			Config:          testAccResourceWorkspaceConfigBasic(),
+			ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+			ResourceName:    "structurizr_workspace.test",
+			ImportStateId:   id,
+			ImportState:     true,
+			ExpectError:     regexp.MustCompile(expectError),
+		}
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			step("workspace", `Expected an identifier of the form`),
+			step("3", `Failed to retrieve Workspace \(id: 3\)`),
+			step("name:Workspace 0003", `No Workspace named "Workspace 0003"`),
+			step("name:Workspace 0001", `2 Workspaces named "Workspace 0001" exist`),
+			step("1:wrong", `Failed to decrypt Workspace \(id: 1\)`),
+			step("2:structurizr", `Failed to decrypt Workspace \(id: 2\)`),
+			{
+				Config:          testAccResourceWorkspaceConfigBasic(),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				ResourceName:    "structurizr_workspace.test",
+				ImportStateId:   "1:structurizr",
+				ImportState:     true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if passphrase := states[0].Attributes["source_passphrase"]; passphrase != "structurizr" {
+						return fmt.Errorf("expected source_passphrase to be imported, got: %q", passphrase)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestResourceWorkspace_Disappears(t *testing.T) {
+	list := &acctest.MockEndpoint{
+		Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+		Response: &acctest.MockResponse{
+			StatusCode:  http.StatusOK,
+			Body:        acctest.MockResourceWorkspaceBasicGet,
+			ContentType: "application/json",
+		},
+	}
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPost, Uri: "/api/workspace", Body: util.StringPtr("")},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicCreate,
+				ContentType: "application/json",
+			},
+		},
+		list,
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicContent,
+				ContentType: "application/json",
+			},
+		},
+		{
+			// The destroy is not refreshing the state, the workspace deleted outside of Terraform is
+			// considered as already deleted.
+			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusNotFound,
+				Body:        `{"success":false,"message":"Workspace 1 does not exist"}`,
+				ContentType: "application/json",
+			},
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccResourceWorkspaceConfigBasic(),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check:           resource.TestCheckResourceAttr("structurizr_workspace.test", "id", "1"),
+			},
+			{
+				// The workspace is deleted outside of Terraform, the refresh removes it from the state
+				// and a re-creation is planned.
+				PreConfig: func() {
+					list.Response.Body = `{"workspaces":[]}`
+				},
+				Config:             testAccResourceWorkspaceConfigBasic(),
+				ConfigVariables:    config.Variables{"host": config.StringVariable(mockServer.URL)},
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestResourceWorkspace_Timeouts(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPost, Uri: "/api/workspace", Body: util.StringPtr("")},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicCreate,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicGet,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicContent,
+				ContentType: "application/json",
+			},
+			Calls: 2,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicDelete,
+				ContentType: "text/plain",
+			},
+			Calls: 1,
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		CheckDestroy: func(state *terraform.State) error {
+			return acctest.AssertMockEndpointsCalls(endpoints)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccResourceWorkspaceConfigTimeouts(),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "id", "1"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "timeouts.create", "1m"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "timeouts.delete", "30s"),
+				),
 			},
 		},
 	})
 }
 
+func TestWorkspaceResource_getWorkspaceByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         int64
+		statusCode int
+		body       string
+		expected   error
+	}{
+		{"Given an existing workspace", 1, http.StatusOK, acctest.MockResourceWorkspaceBasicGet, nil},
+		{"Given a missing workspace", 2, http.StatusOK, acctest.MockResourceWorkspaceBasicGet, errWorkspaceNotFound},
+		{"Given no workspaces", 1, http.StatusOK, `{"workspaces":[]}`, errWorkspaceNotFound},
+		{"Given an API failure", 1, http.StatusUnauthorized, `{"message":"Unauthorized"}`, model.APIErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := acctest.NewMockServer(t, "Workspace API", []*acctest.MockEndpoint{
+				{
+					Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+					Response: &acctest.MockResponse{
+						StatusCode:  tt.statusCode,
+						Body:        tt.body,
+						ContentType: "application/json",
+					},
+				},
+			})
+			defer mockServer.Close()
+
+			baseURL, _ := url.Parse(mockServer.URL)
+			apiClient, err := api.NewClient(&api.Config{AdminAPIKey: "key", BaseURL: baseURL})
+			assert.NoError(t, err)
+
+			r := &workspaceResource{clientManager: client.NewManager(apiClient, nil, client.DefaultMaxParallelOperations, client.PushConfig{})}
+
+			workspace, err := r.getWorkspaceByID(context.Background(), tt.id)
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.id, workspace.ID)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWorkspaceResource_contentChanged(t *testing.T) {
+	state := WorkspaceResourceModel{
+		Source:           types.StringValue("testdata/workspace.dsl"),
+		SourceChecksum:   types.StringValue("ba47f1dae6946adbad62496b6dd6b7a3"),
+		SourceContent:    types.StringNull(),
+		SourceFormat:     types.StringNull(),
+		SourcePassphrase: types.StringValue("structurizr"),
+		PinnedVersion:    types.StringNull(),
+		PreserveLayout:   types.BoolNull(),
+	}
+
+	tests := []struct {
+		name     string
+		update   func(plan *WorkspaceResourceModel)
+		expected bool
+	}{
+		{"Given the same content", func(plan *WorkspaceResourceModel) {}, false},
+		{"Given another checksum", func(plan *WorkspaceResourceModel) { plan.SourceChecksum = types.StringValue("other") }, true},
+		{"Given another pinned version", func(plan *WorkspaceResourceModel) { plan.PinnedVersion = types.StringValue("1") }, true},
+		{"Given another passphrase", func(plan *WorkspaceResourceModel) { plan.SourcePassphrase = types.StringValue("other") }, true},
+		{"Given a removed passphrase", func(plan *WorkspaceResourceModel) { plan.SourcePassphrase = types.StringNull() }, true},
+		{"Given another push option", func(plan *WorkspaceResourceModel) { plan.PreserveLayout = types.BoolValue(true) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := state
+			tt.update(&plan)
+
+			assert.Equal(t, tt.expected, contentChanged(state, plan))
+		})
+	}
+}
+
+func TestWorkspaceResource_UpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &workspaceResource{}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	req := fwresource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(`{
+  "id": 1,
+  "name": "Workspace 0001",
+  "description": "Description",
+  "api_key": "691e0542-5c4d-4f74-be4a-38134a0aa0bf",
+  "api_secret": "8497f68e-75b9-431b-b067-cf86a074205c",
+  "public_url": "/share/1",
+  "private_url": "/workspace/1",
+  "shareable_url": "",
+  "source": "testdata/workspace.dsl",
+  "source_content": null,
+  "source_format": null,
+  "source_checksum": "ba47f1dae6946adbad62496b6dd6b7a3",
+  "source_passphrase": null,
+  "pinned_version": null,
+  "preserve_layout": null,
+  "archive": null,
+  "revision": 2,
+  "last_updated": "Friday, 03-May-24 10:00:00 UTC",
+  "timeouts": null
+}`)}}
+	var resp fwresource.UpgradeStateResponse
+
+	upgradeWorkspaceStateV0(ctx, req, &resp)
+
+	assert.False(t, resp.Diagnostics.HasError())
+
+	value, err := resp.DynamicValue.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	assert.NoError(t, err)
+
+	var state map[string]tftypes.Value
+	assert.NoError(t, value.As(&state))
+	assert.NotContains(t, state, "last_updated")
+	assert.True(t, state["last_modified_date"].IsNull())
+	assert.True(t, state["revision"].Equal(tftypes.NewValue(tftypes.Number, 2)))
+	assert.Equal(t, tftypes.NewValue(tftypes.String, "testdata/workspace.dsl"), state["source"])
+}
+
 func TestResourceWorkspace_Update(t *testing.T) {
 	endpoints := []*acctest.MockEndpoint{
 		{
@@ -167,6 +532,15 @@ func TestResourceWorkspace_Update(t *testing.T) {
 			},
 			Calls: 4,
 		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceContent,
+				ContentType: "application/json",
+			},
+			Calls: 5,
+		},
 		{
 			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
 			Response: &acctest.MockResponse{
@@ -199,7 +573,7 @@ func TestResourceWorkspace_Update(t *testing.T) {
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "private_url", "/workspace/1"),
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "public_url", "/share/1"),
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "shareable_url", ""),
-					resource.TestCheckResourceAttrSet("structurizr_workspace.test", "last_updated"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "last_modified_date", "2024-05-01T10:00:00Z"),
 				),
 			},
 			// Update
@@ -217,22 +591,909 @@ func TestResourceWorkspace_Update(t *testing.T) {
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "shareable_url", ""),
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "source", "testdata/workspace.dsl"),
 					resource.TestCheckResourceAttr("structurizr_workspace.test", "source_checksum", "ba47f1dae6946adbad62496b6dd6b7a3"),
-					resource.TestCheckResourceAttrSet("structurizr_workspace.test", "last_updated"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "last_modified_date", "2024-05-01T10:00:00Z"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "last_modified_user", ""),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "last_modified_agent", "structurizr-cli/2024.03.03"),
 				),
 			},
 		},
 	})
 }
 
-func testAccResourceWorkspaceConfigBasic() string {
-	return util.ConfigCompose(testAccProvider(), `resource "structurizr_workspace" "test" {}`)
+func TestResourceWorkspace_UpdateSettings(t *testing.T) {
+	push := &acctest.MockEndpoint{
+		Request: &acctest.MockRequest{Method: http.MethodPut, Uri: "/api/workspace/1"},
+		Response: &acctest.MockResponse{
+			StatusCode:  http.StatusOK,
+			Body:        acctest.MockResourceWorkspaceWithSourceUpdate,
+			ContentType: "application/json",
+		},
+		Calls: 1,
+	}
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPost, Uri: "/api/workspace", Body: util.StringPtr("")},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicCreate,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		push,
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceGet,
+				ContentType: "application/json",
+			},
+			Calls: 8,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceContent,
+				ContentType: "application/json",
+			},
+			Calls: 8,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicDelete,
+				ContentType: "text/plain",
+			},
+			Calls: 1,
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	dir := t.TempDir()
+	variables := config.Variables{"host": config.StringVariable(mockServer.URL), "dir": config.StringVariable(dir)}
+
+	// The settings of the resource are stored without pushing the source again, which would bump the revision
+	noPush := func(*terraform.State) error {
+		if err := acctest.AssertMockEndpointsCalls([]*acctest.MockEndpoint{push}); err != nil {
+			return fmt.Errorf("expected the source not to be pushed again: %w", err)
+		}
+		return nil
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		CheckDestroy: func(state *terraform.State) error {
+			return acctest.AssertMockEndpointsCalls(endpoints)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccResourceWorkspaceConfigSourceSettings(""),
+				ConfigVariables: variables,
+				Check:           resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+			},
+			{
+				Config:          testAccResourceWorkspaceConfigSourceSettings(`archive_on_destroy = var.dir`),
+				ConfigVariables: variables,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					noPush,
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "archive_on_destroy", dir),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+				),
+			},
+			// The options only apply to the next push of the source
+			{
+				Config:          testAccResourceWorkspaceConfigSourceSettings(`preserve_layout = true`),
+				ConfigVariables: variables,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					noPush,
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "preserve_layout", "true"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+				),
+			},
+			{
+				Config: testAccResourceWorkspaceConfigSourceSettings(`timeouts {
+        update = "5m"
+    }`),
+				ConfigVariables: variables,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					noPush,
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "timeouts.update", "5m"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+				),
+			},
+		},
+	})
 }
 
-func testAccResourceWorkspaceConfigBasicUpdate() string {
-	return util.ConfigCompose(testAccProvider(), `
-resource "structurizr_workspace" "test" {
-    source          = "testdata/workspace.dsl"
-    source_checksum = "ba47f1dae6946adbad62496b6dd6b7a3"
+func TestResourceWorkspace_Drift(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		attr      string
+		value     string
+		endpoints []*acctest.MockEndpoint
+		calls     int
+	}{
+		{
+			name:   "Given a source",
+			config: testAccResourceWorkspaceConfigBasicUpdate(),
+			attr:   "source_checksum",
+			value:  "ba47f1dae6946adbad62496b6dd6b7a3",
+			calls:  7,
+		},
+		{
+			name:   "Given an inline content",
+			config: testAccResourceWorkspaceConfigSourceContent(`workspace "Workspace DSL" {}`, ""),
+			attr:   "source_content",
+			value:  `workspace "Workspace DSL" {}`,
+			calls:  7,
+		},
+		{
+			name:   "Given a pinned version",
+			config: testAccResourceWorkspaceConfigPinnedVersion("20240501100000000"),
+			attr:   "pinned_version",
+			value:  "20240501100000000",
+			endpoints: []*acctest.MockEndpoint{
+				{
+					Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1/versions"},
+					Response: &acctest.MockResponse{
+						StatusCode:  http.StatusOK,
+						Body:        acctest.MockDataSourceWorkspaceVersions,
+						ContentType: "application/json",
+					},
+					Calls: 4,
+				},
+				{
+					Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1?version=20240501100000000"},
+					Response: &acctest.MockResponse{
+						StatusCode:  http.StatusOK,
+						Body:        acctest.MockResourceWorkspaceVersionContent,
+						ContentType: "application/json",
+					},
+					Calls: 2,
+				},
+			},
+			calls: 9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := &acctest.MockEndpoint{
+				Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+				Response: &acctest.MockResponse{
+					StatusCode:  http.StatusOK,
+					Body:        acctest.MockResourceWorkspaceWithSourceContent,
+					ContentType: "application/json",
+				},
+				Calls: tt.calls,
+			}
+			endpoints := append([]*acctest.MockEndpoint{
+				{
+					Request: &acctest.MockRequest{Method: http.MethodPost, Uri: "/api/workspace", Body: util.StringPtr("")},
+					Response: &acctest.MockResponse{
+						StatusCode:  http.StatusOK,
+						Body:        acctest.MockResourceWorkspaceBasicCreate,
+						ContentType: "application/json",
+					},
+					Calls: 1,
+				},
+				{
+					Request: &acctest.MockRequest{Method: http.MethodPut, Uri: "/api/workspace/1"},
+					Response: &acctest.MockResponse{
+						StatusCode:  http.StatusOK,
+						Body:        acctest.MockResourceWorkspaceWithSourceUpdate,
+						ContentType: "application/json",
+					},
+					Calls: 2,
+				},
+				{
+					Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+					Response: &acctest.MockResponse{
+						StatusCode:  http.StatusOK,
+						Body:        acctest.MockResourceWorkspaceWithSourceGet,
+						ContentType: "application/json",
+					},
+					Calls: 7,
+				},
+				content,
+				{
+					Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
+					Response: &acctest.MockResponse{
+						StatusCode:  http.StatusOK,
+						Body:        acctest.MockResourceWorkspaceBasicDelete,
+						ContentType: "text/plain",
+					},
+					Calls: 1,
+				},
+			}, tt.endpoints...)
+
+			mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+			defer mockServer.Close()
+
+			resource.ParallelTest(t, resource.TestCase{
+				ProtoV6ProviderFactories: protoV6ProviderFactories(),
+				CheckDestroy: func(state *terraform.State) error {
+					return acctest.AssertMockEndpointsCalls(endpoints)
+				},
+				Steps: []resource.TestStep{
+					{
+						Config:          tt.config,
+						ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+							resource.TestCheckResourceAttr("structurizr_workspace.test", "remote_revision", "2"),
+						),
+					},
+					{
+						// The workspace is edited in the Structurizr UI, the refresh records the remote revision
+						// next to the pushed one and the drift is planned as an update.
+						PreConfig: func() {
+							content.Response.Body = strings.Replace(content.Response.Body, `"revision": 2`, `"revision": 3`, 1)
+						},
+						RefreshState:       true,
+						ExpectNonEmptyPlan: true,
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("structurizr_workspace.test", tt.attr, tt.value),
+							resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+							resource.TestCheckResourceAttr("structurizr_workspace.test", "remote_revision", "3"),
+						),
+					},
+					{
+						// Applying the drift pushes the content again, the inputs are kept as configured.
+						Config:          tt.config,
+						ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+						ConfigPlanChecks: resource.ConfigPlanChecks{
+							PreApply: []plancheck.PlanCheck{
+								plancheck.ExpectResourceAction("structurizr_workspace.test", plancheck.ResourceActionUpdate),
+								plancheck.ExpectKnownValue(
+									"structurizr_workspace.test",
+									tfjsonpath.New(tt.attr),
+									knownvalue.StringExact(tt.value),
+								),
+								plancheck.ExpectUnknownValue("structurizr_workspace.test", tfjsonpath.New("revision")),
+								plancheck.ExpectUnknownValue("structurizr_workspace.test", tfjsonpath.New("last_modified_date")),
+							},
+						},
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("structurizr_workspace.test", tt.attr, tt.value),
+							resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "3"),
+							resource.TestCheckResourceAttr("structurizr_workspace.test", "remote_revision", "3"),
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestResourceWorkspace_PinnedVersion(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPost, Uri: "/api/workspace", Body: util.StringPtr("")},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicCreate,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPut, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceUpdate,
+				ContentType: "application/json",
+			},
+			Calls: 2,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceGet,
+				ContentType: "application/json",
+			},
+			Calls: 6,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1/versions"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockDataSourceWorkspaceVersions,
+				ContentType: "application/json",
+			},
+			Calls: 3,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1?version=20240501100000000"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceVersionContent,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceContent,
+				ContentType: "application/json",
+			},
+			Calls: 7,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicDelete,
+				ContentType: "text/plain",
+			},
+			Calls: 1,
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		CheckDestroy: func(state *terraform.State) error {
+			return acctest.AssertMockEndpointsCalls(endpoints)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccResourceWorkspaceConfigBasicUpdate(),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check:           resource.TestCheckNoResourceAttr("structurizr_workspace.test", "pinned_version"),
+			},
+			{
+				Config:          testAccResourceWorkspaceConfigPinnedVersion("20240430100000000"),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				ExpectError: regexp.MustCompile(
+					`(?s)The version "20240430100000000" of the Workspace \(id: 1\) is not kept.*20240502090000000,\s+20240501100000000`,
+				),
+			},
+			{
+				Config:          testAccResourceWorkspaceConfigPinnedVersion("20240501100000000"),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "pinned_version", "20240501100000000"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceWorkspace_SourceContent(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPost, Uri: "/api/workspace", Body: util.StringPtr("")},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicCreate,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPut, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceUpdate,
+				ContentType: "application/json",
+			},
+			Calls: 2,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceGet,
+				ContentType: "application/json",
+			},
+			Calls: 5,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceContent,
+				ContentType: "application/json",
+			},
+			Calls: 5,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicDelete,
+				ContentType: "text/plain",
+			},
+			Calls: 1,
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		CheckDestroy: func(state *terraform.State) error {
+			return acctest.AssertMockEndpointsCalls(endpoints)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccResourceWorkspaceConfigSourceContent(`workspace "Workspace DSL" {}`, ""),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(
+							"structurizr_workspace.test",
+							tfjsonpath.New("name"),
+							knownvalue.StringExact("Workspace DSL"),
+						),
+						plancheck.ExpectUnknownValue("structurizr_workspace.test", tfjsonpath.New("description")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "name", "Workspace DSL"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "source_content", `workspace "Workspace DSL" {}`),
+					resource.TestCheckNoResourceAttr("structurizr_workspace.test", "source_format"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+				),
+			},
+			// Changing the content pushes it again
+			{
+				Config:          testAccResourceWorkspaceConfigSourceContent(`{"name": "Workspace DSL"}`, "json"),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(
+							"structurizr_workspace.test",
+							tfjsonpath.New("name"),
+							knownvalue.StringExact("Workspace DSL"),
+						),
+						plancheck.ExpectUnknownValue("structurizr_workspace.test", tfjsonpath.New("revision")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "id", "1"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "preserve_layout", "true"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "archive", "false"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "source_content", `{"name": "Workspace DSL"}`),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "source_format", "json"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceWorkspace_SourceContentConflicts(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: util.ConfigCompose(testAccProvider(), `
+resource "structurizr_workspace" "test" {
+    source          = "testdata/workspace.dsl"
+    source_checksum = "ba47f1dae6946adbad62496b6dd6b7a3"
+    source_content  = "workspace {}"
+}
+`),
+				ConfigVariables: config.Variables{"host": config.StringVariable("http://localhost")},
+				ExpectError:     regexp.MustCompile(`These attributes cannot be configured together`),
+			},
+			{
+				Config: util.ConfigCompose(testAccProvider(), `
+resource "structurizr_workspace" "test" {
+    source_content = "workspace {}"
+    source_format  = "yaml"
+}
+`),
+				ConfigVariables: config.Variables{"host": config.StringVariable("http://localhost")},
+				ExpectError:     regexp.MustCompile(`value must be one of`),
+			},
+		},
+	})
+}
+
+func TestResourceWorkspace_Generated(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPost, Uri: "/api/workspace", Body: util.StringPtr("")},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicCreate,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{
+				Method: http.MethodPut,
+				Uri:    "/api/workspace/1",
+				Body:   util.StringPtr(`{"description":"Managed Workspace by DSL","name":"Workspace DSL"}`),
+			},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceUpdate,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceGet,
+				ContentType: "application/json",
+			},
+			Calls: 2,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceContent,
+				ContentType: "application/json",
+			},
+			Calls: 2,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicDelete,
+				ContentType: "text/plain",
+			},
+			Calls: 1,
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		CheckDestroy: func(state *terraform.State) error {
+			return acctest.AssertMockEndpointsCalls(endpoints)
+		},
+		Steps: []resource.TestStep{
+			// A generated workspace has no source
+			{
+				Config: util.ConfigCompose(testAccProvider(), `
+resource "structurizr_workspace" "test" {
+    name   = "Workspace DSL"
+    source = "testdata/workspace.dsl"
+}
+`),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				ExpectError:     regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			{
+				Config: util.ConfigCompose(testAccProvider(), `
+resource "structurizr_workspace" "test" {
+    name        = "Workspace DSL"
+    description = "Managed Workspace by DSL"
+}
+`),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "id", "1"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "name", "Workspace DSL"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "description", "Managed Workspace by DSL"),
+					resource.TestCheckNoResourceAttr("structurizr_workspace.test", "source"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceWorkspace_DeletionProtection(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPost, Uri: "/api/workspace", Body: util.StringPtr("")},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicCreate,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPut, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceUpdate,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceGet,
+				ContentType: "application/json",
+			},
+			Calls: 7,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceContent,
+				ContentType: "application/json",
+			},
+			Calls: 8,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicDelete,
+				ContentType: "text/plain",
+			},
+			Calls: 1,
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	dir := filepath.Join(t.TempDir(), "archives")
+	variables := config.Variables{"host": config.StringVariable(mockServer.URL), "dir": config.StringVariable(dir)}
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		CheckDestroy: func(state *terraform.State) error {
+			// The workspace is archived as received before being deleted
+			names, err := filepath.Glob(filepath.Join(dir, "structurizr-1-*.json"))
+			if err != nil || len(names) != 1 {
+				return fmt.Errorf("expected the workspace to be archived once into %s, got: %v (%v)", dir, names, err)
+			}
+
+			archived, err := os.ReadFile(names[0])
+			if err != nil {
+				return err
+			}
+			if string(archived) != acctest.MockResourceWorkspaceWithSourceContent {
+				return fmt.Errorf("unexpected archive content: %s", archived)
+			}
+
+			return acctest.AssertMockEndpointsCalls(endpoints)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccResourceWorkspaceConfigDeletionProtection(false),
+				ConfigVariables: variables,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "deletion_protection", "false"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "archive_on_destroy", dir),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+				),
+			},
+			// Protecting and lifting the protection only change the state, the source is not pushed again
+			{
+				Config:          testAccResourceWorkspaceConfigDeletionProtection(true),
+				ConfigVariables: variables,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "deletion_protection", "true"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+				),
+			},
+			{
+				Config:          testAccResourceWorkspaceConfigDeletionProtection(true),
+				ConfigVariables: variables,
+				Destroy:         true,
+				ExpectError:     regexp.MustCompile(`Workspace is protected from deletion`),
+			},
+			{
+				Config:          testAccResourceWorkspaceConfigDeletionProtection(false),
+				ConfigVariables: variables,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "deletion_protection", "false"),
+					resource.TestCheckResourceAttr("structurizr_workspace.test", "revision", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceWorkspace_SourceChecksum(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPost, Uri: "/api/workspace", Body: util.StringPtr("")},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicCreate,
+				ContentType: "application/json",
+			},
+			Calls: 1,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodPut, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceUpdate,
+				ContentType: "application/json",
+			},
+			Calls: 2,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceGet,
+				ContentType: "application/json",
+			},
+			Calls: 5,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceWithSourceContent,
+				ContentType: "application/json",
+			},
+			Calls: 5,
+		},
+		{
+			Request: &acctest.MockRequest{Method: http.MethodDelete, Uri: "/api/workspace/1"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockResourceWorkspaceBasicDelete,
+				ContentType: "text/plain",
+			},
+			Calls: 1,
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	name := writeWorkspaceWithIncludes(t)
+	checksum, err := source.Checksum(name)
+	assert.NoError(t, err)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		CheckDestroy: func(state *terraform.State) error {
+			return acctest.AssertMockEndpointsCalls(endpoints)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceWorkspaceConfigSource(),
+				ConfigVariables: config.Variables{
+					"host": config.StringVariable(mockServer.URL),
+					"path": config.StringVariable(name),
+				},
+				Check: resource.TestCheckResourceAttr("structurizr_workspace.test", "source_checksum", checksum),
+			},
+			// Editing an included file pushes the source again
+			{
+				PreConfig: func() {
+					assert.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(name), "model.dsl"), []byte(`admin = person "Admin"`), 0o644))
+				},
+				Config: testAccResourceWorkspaceConfigSource(),
+				ConfigVariables: config.Variables{
+					"host": config.StringVariable(mockServer.URL),
+					"path": config.StringVariable(name),
+				},
+				Check: resource.TestCheckResourceAttrWith("structurizr_workspace.test", "source_checksum", func(value string) error {
+					if value == checksum {
+						return fmt.Errorf("expected the checksum to change after editing an included file")
+					}
+					return nil
+				}),
+			},
+		},
+	})
+}
+
+func TestResourceWorkspace_InvalidSource(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccResourceWorkspaceConfigSourceContent("workspace {\n    model {\n        !invalid\n    }\n}", ""),
+				ConfigVariables: config.Variables{"host": config.StringVariable("http://localhost")},
+				PlanOnly:        true,
+				ExpectError:     regexp.MustCompile(`Invalid Workspace source`),
+			},
+		},
+	})
+}
+
+func testAccResourceWorkspaceConfigBasic() string {
+	return util.ConfigCompose(testAccProvider(), `resource "structurizr_workspace" "test" {}`)
+}
+
+func testAccResourceWorkspaceConfigDeletionProtection(protected bool) string {
+	return util.ConfigCompose(testAccProvider(), fmt.Sprintf(`
+variable "dir" {}
+
+resource "structurizr_workspace" "test" {
+    source              = "testdata/workspace.dsl"
+    source_checksum     = "ba47f1dae6946adbad62496b6dd6b7a3"
+    deletion_protection = %t
+    archive_on_destroy  = var.dir
+}
+`, protected))
+}
+
+func testAccResourceWorkspaceConfigTimeouts() string {
+	return util.ConfigCompose(testAccProvider(), `
+resource "structurizr_workspace" "test" {
+    timeouts {
+        create = "1m"
+        delete = "30s"
+    }
+}
+`)
+}
+
+func testAccResourceWorkspaceConfigBasicUpdate() string {
+	return util.ConfigCompose(testAccProvider(), `
+resource "structurizr_workspace" "test" {
+    source          = "testdata/workspace.dsl"
+    source_checksum = "ba47f1dae6946adbad62496b6dd6b7a3"
+}
+`)
+}
+
+func testAccResourceWorkspaceConfigSourceSettings(settings string) string {
+	return util.ConfigCompose(testAccProvider(), fmt.Sprintf(`
+variable "dir" {}
+
+resource "structurizr_workspace" "test" {
+    source          = "testdata/workspace.dsl"
+    source_checksum = "ba47f1dae6946adbad62496b6dd6b7a3"
+    %s
+}
+`, settings))
+}
+
+func testAccResourceWorkspaceConfigPinnedVersion(version string) string {
+	return util.ConfigCompose(testAccProvider(), fmt.Sprintf(`
+resource "structurizr_workspace" "test" {
+    source          = "testdata/workspace.dsl"
+    source_checksum = "ba47f1dae6946adbad62496b6dd6b7a3"
+    pinned_version  = %q
+}
+`, version))
+}
+
+func testAccResourceWorkspaceConfigSourceContent(content string, format string) string {
+	// An explicit format also preserves the layout, without archive
+	attrFormat := ""
+	if format != "" {
+		attrFormat = fmt.Sprintf("source_format   = %q\n    preserve_layout = true\n    archive         = false", format)
+	}
+
+	return util.ConfigCompose(testAccProvider(), fmt.Sprintf(`
+resource "structurizr_workspace" "test" {
+    source_content = %q
+    %s
+}
+`, content, attrFormat))
+}
+
+func testAccResourceWorkspaceConfigSource() string {
+	return util.ConfigCompose(testAccProvider(), `
+variable "path" {}
+
+resource "structurizr_workspace" "test" {
+    source = var.path
 }
 `)
 }
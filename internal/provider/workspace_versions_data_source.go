@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// WorkspaceVersionModel represents a previous version of a workspace kept by the structurizr
+type WorkspaceVersionModel struct {
+	VersionID types.String `tfsdk:"version_id"`
+	Timestamp types.String `tfsdk:"timestamp"`
+}
+
+// WorkspaceVersionsModel represents the versions of a workspace kept by the structurizr
+type WorkspaceVersionsModel struct {
+	ID        types.Int64             `tfsdk:"id"`
+	APIKey    types.String            `tfsdk:"api_key"`
+	APISecret types.String            `tfsdk:"api_secret"`
+	Versions  []WorkspaceVersionModel `tfsdk:"versions"`
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                     = &workspaceVersionsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &workspaceVersionsDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &workspaceVersionsDataSource{}
+)
+
+// NewWorkspaceVersionsDataSource is a helper function to simplify the provider implementation.
+func NewWorkspaceVersionsDataSource() datasource.DataSource {
+	return &workspaceVersionsDataSource{}
+}
+
+// workspaceVersionsDataSource is the data source implementation.
+type workspaceVersionsDataSource struct {
+	client *client.Manager
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *workspaceVersionsDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	c, ok := req.ProviderData.(*client.Manager)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected *client.Manager, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+
+		return
+	}
+
+	d.client = c
+}
+
+// ConfigValidators returns a list of functions which will all be performed during validation.
+func (d *workspaceVersionsDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		// Validate the workspace credentials are either both null or both known values.
+		datasourcevalidator.RequiredTogether(
+			path.MatchRoot("api_key"),
+			path.MatchRoot("api_secret"),
+		),
+	}
+}
+
+// Metadata returns the data source type name. It can be used to register other type of information
+func (d *workspaceVersionsDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_versions"
+}
+
+// Schema defines the schema for the data source.
+func (d *workspaceVersionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the previous versions of a Workspace kept by the remote server " +
+			"(see `structurizr.maxWorkspaceVersions`), the latest version comes first.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Required:    true,
+				Description: "The identifier of the Workspace to list the versions from.",
+			},
+			"api_key": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "The API key specific to the Workspace. " +
+					"When omitted, it is looked up using the admin API key of the provider.",
+			},
+			"api_secret": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "The API secret key specific to the Workspace. " +
+					"When omitted, it is looked up using the admin API key of the provider.",
+			},
+			"versions": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"version_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The identifier of the version, which can be used as `pinned_version` of a Workspace.",
+						},
+						"timestamp": schema.StringAttribute{
+							Computed:    true,
+							Description: "The date when the version was saved.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read fetches the Terraform state with the latest data.
+func (d *workspaceVersionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state WorkspaceVersionsModel
+	if resp.Diagnostics.Append(req.Config.Get(ctx, &state)...); resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("[READ] State: %s", state))
+
+	id := state.ID.ValueInt64()
+	key, secret, err := workspaceCredentials(ctx, d.client, id, state.APIKey, state.APISecret)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Unable to retrieve structurizr workspace credentials",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %d) credentials with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	res, err := d.client.GetWorkspaceVersions(ctx, id, key, secret)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read structurizr workspace versions",
+			fmt.Sprintf("Failed to retrieve Workspace (id: %d) versions with error: %s", id, errorDetail(err)),
+		)
+		return
+	}
+
+	state.Versions = []WorkspaceVersionModel{}
+	for _, version := range res.Versions {
+		state.Versions = append(state.Versions, WorkspaceVersionModel{
+			VersionID: types.StringValue(version.VersionID),
+			Timestamp: types.StringValue(version.Timestamp),
+		})
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("[READ] Storing Workspace versions: %+v", state))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
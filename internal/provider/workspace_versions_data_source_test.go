@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"github.com/fstaoe/terraform-provider-structurizr/internal/acctest"
+	"github.com/fstaoe/terraform-provider-structurizr/internal/util"
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"net/http"
+	"testing"
+)
+
+func TestDataSourceWorkspaceVersions_Basic(t *testing.T) {
+	endpoints := []*acctest.MockEndpoint{
+		{
+			Request: &acctest.MockRequest{Method: http.MethodGet, Uri: "/api/workspace/1/versions"},
+			Response: &acctest.MockResponse{
+				StatusCode:  http.StatusOK,
+				Body:        acctest.MockDataSourceWorkspaceVersions,
+				ContentType: "application/json",
+			},
+		},
+	}
+
+	mockServer := acctest.NewMockServer(t, "Workspace API", endpoints)
+	defer mockServer.Close()
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:          testAccDataSourceWorkspaceVersionsConfig(),
+				ConfigVariables: config.Variables{"host": config.StringVariable(mockServer.URL)},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.structurizr_workspace_versions.test", "id", "1"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_versions.test", "versions.#", "2"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_versions.test", "versions.0.version_id", "20240502090000000"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_versions.test", "versions.0.timestamp", "2024-05-02T09:00:00Z"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_versions.test", "versions.1.version_id", "20240501100000000"),
+					resource.TestCheckResourceAttr("data.structurizr_workspace_versions.test", "versions.1.timestamp", "2024-05-01T10:00:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceWorkspaceVersionsConfig() string {
+	return util.ConfigCompose(testAccProvider(), `
+data "structurizr_workspace_versions" "test" {
+    id         = 1
+    api_key    = "691e0542-5c4d-4f74-be4a-38134a0aa0bf"
+    api_secret = "8497f68e-75b9-431b-b067-cf86a074205c"
+}
+`)
+}
@@ -0,0 +1,191 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Checksum returns a digest of a workspace source and of every file it depends on: the files and directories pulled
+// in by the !include, !docs and !adrs directives of DSL workspaces, recursively, and the workspace it extends.
+// Remote dependencies (URLs) and paths built from constants or variables cannot be resolved, they are only covered
+// by the text of the directive. The digest does not depend on the location of the source, only on the paths of its
+// dependencies relative to it and on their content, so it only changes when the pushed workspace would change.
+func Checksum(name string) (string, error) {
+	w := &walker{root: filepath.Dir(name), sums: map[string][sha256.Size]byte{}}
+	if err := w.file(name, true); err != nil {
+		return "", err
+	}
+
+	paths := make([]string, 0, len(w.sums))
+	for p := range w.sums {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, p := range paths {
+		_, _ = fmt.Fprintf(h, "%s\x00%x\n", p, w.sums[p])
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// walker collects the checksums of the files a workspace source depends on, keyed by their path relative to the source
+type walker struct {
+	root string
+	sums map[string][sha256.Size]byte
+}
+
+// file hashes a file, and its dependencies when it is parsed as DSL. Every file is only visited once, so circular
+// includes do not loop forever.
+func (w *walker) file(name string, parse bool) error {
+	key, err := filepath.Rel(w.root, name)
+	if err != nil {
+		key = name
+	}
+	key = filepath.ToSlash(key)
+
+	if _, ok := w.sums[key]; ok {
+		return nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("failed to read workspace source: %w", err)
+	}
+	w.sums[key] = sha256.Sum256(data)
+
+	if !parse || Detect(data) != FormatDSL {
+		return nil
+	}
+
+	for _, d := range dependencies(data) {
+		if isRemote(d.path) || strings.Contains(d.path, "${") {
+			continue
+		}
+
+		p := d.path
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(filepath.Dir(name), p)
+		}
+
+		if err = w.path(p, d.parse); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// path hashes a file, or every file of a directory recursively. Only DSL files are parsed in directories, as any
+// other file such as documentation is never included in a workspace as DSL.
+func (w *walker) path(name string, parse bool) error {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return fmt.Errorf("failed to read workspace source: %w", err)
+	}
+
+	if !fi.IsDir() {
+		return w.file(name, parse)
+	}
+
+	return filepath.WalkDir(name, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		return w.file(p, parse && strings.EqualFold(filepath.Ext(p), ".dsl"))
+	})
+}
+
+// dependency is a file or a directory referenced by a DSL workspace
+type dependency struct {
+	path string
+	// parse reports whether the dependency is DSL which may reference further dependencies
+	parse bool
+}
+
+// dependencies returns the files and directories referenced by the directives of a DSL workspace.
+// Comments are skipped, so commented out directives are not dependencies.
+func dependencies(data []byte) []dependency {
+	var deps []dependency
+	inComment := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if inComment {
+			if strings.HasSuffix(line, "*/") {
+				inComment = false
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "/*") {
+			inComment = !strings.HasSuffix(line, "*/")
+			continue
+		}
+
+		tokens := tokenize(line)
+		if len(tokens) < 2 {
+			continue
+		}
+
+		switch strings.ToLower(tokens[0]) {
+		case "!include":
+			deps = append(deps, dependency{path: tokens[1], parse: true})
+		case "!docs", "!adrs":
+			deps = append(deps, dependency{path: tokens[1]})
+		case "workspace":
+			if len(tokens) > 2 && strings.EqualFold(tokens[1], "extends") {
+				deps = append(deps, dependency{path: tokens[2], parse: true})
+			}
+		}
+	}
+
+	return deps
+}
+
+// tokenize splits a line of DSL into its tokens, the double quotes around a token are removed
+func tokenize(line string) []string {
+	var tokens []string
+	var token strings.Builder
+	quoted, started := false, false
+
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted, started = !quoted, true
+		case !quoted && (r == ' ' || r == '\t'):
+			if started {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				started = false
+			}
+		default:
+			token.WriteRune(r)
+			started = true
+		}
+	}
+
+	if started {
+		tokens = append(tokens, token.String())
+	}
+
+	return tokens
+}
+
+// isRemote reports whether a dependency is fetched from a URL
+func isRemote(p string) bool {
+	return strings.HasPrefix(p, "https://") || strings.HasPrefix(p, "http://")
+}
@@ -0,0 +1,122 @@
+package source
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTree writes files relative to a temporary directory and returns the directory
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		assert.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+
+	return dir
+}
+
+func TestChecksum(t *testing.T) {
+	tree := map[string]string{
+		"workspace.dsl": `workspace extends "base.dsl" {
+    !docs docs
+    model {
+        !include "model/people.dsl"
+        !include model/systems
+        // !include missing.dsl
+        /*
+        !include missing.dsl
+        */
+        !include https://example.com/remote.dsl
+        !include ${MODEL}.dsl
+    }
+}`,
+		"base.dsl": `workspace {
+    !adrs "decisions"
+}`,
+		"model/people.dsl":          `user = person "User"`,
+		"model/systems/billing.dsl": `!include ../people.dsl`,
+		"model/systems/README.md":   `!include missing.dsl`,
+		"docs/01-context.md":        `# Context`,
+		"decisions/0001-record.md":  `# 1. Record architecture decisions`,
+		"unused.dsl":                `unused = person "Unused"`,
+	}
+
+	dir := writeTree(t, tree)
+	expected, err := Checksum(filepath.Join(dir, "workspace.dsl"))
+	assert.NoError(t, err)
+	assert.Len(t, expected, 64)
+
+	t.Run("Given the same tree in another location", func(t *testing.T) {
+		actual, err := Checksum(filepath.Join(writeTree(t, tree), "workspace.dsl"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	for _, name := range []string{
+		"workspace.dsl",
+		"base.dsl",
+		"model/people.dsl",
+		"model/systems/billing.dsl",
+		"model/systems/README.md",
+		"docs/01-context.md",
+		"decisions/0001-record.md",
+	} {
+		t.Run("Given a modified "+name, func(t *testing.T) {
+			dir := writeTree(t, tree)
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte("modified"), 0o644))
+
+			actual, err := Checksum(filepath.Join(dir, "workspace.dsl"))
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, expected, actual)
+		})
+	}
+
+	t.Run("Given a modified file which is not a dependency", func(t *testing.T) {
+		dir := writeTree(t, tree)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "unused.dsl"), []byte("modified"), 0o644))
+
+		actual, err := Checksum(filepath.Join(dir, "workspace.dsl"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("Given a JSON workspace", func(t *testing.T) {
+		dir := writeTree(t, map[string]string{"workspace.json": `{"name": "!include missing.dsl"}`})
+
+		actual, err := Checksum(filepath.Join(dir, "workspace.json"))
+
+		assert.NoError(t, err)
+		assert.Len(t, actual, 64)
+	})
+
+	t.Run("Given circular includes", func(t *testing.T) {
+		dir := writeTree(t, map[string]string{"a.dsl": "!include b.dsl", "b.dsl": "!include a.dsl"})
+
+		_, err := Checksum(filepath.Join(dir, "a.dsl"))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Given a missing dependency", func(t *testing.T) {
+		dir := writeTree(t, map[string]string{"workspace.dsl": "!include missing.dsl"})
+
+		_, err := Checksum(filepath.Join(dir, "workspace.dsl"))
+
+		assert.ErrorContains(t, err, "missing.dsl")
+	})
+
+	t.Run("Given a missing source", func(t *testing.T) {
+		_, err := Checksum(filepath.Join(t.TempDir(), "workspace.dsl"))
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,162 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Header is the name and the description declared by a workspace source
+type Header struct {
+	Name        string
+	Description string
+	// HasName and HasDescription report whether the name and the description are declared by the source itself,
+	// otherwise they are only known once the workspace is pushed
+	HasName        bool
+	HasDescription bool
+}
+
+// ReadHeader returns the name and the description declared by a workspace source: the `workspace "name" "description"`
+// header of DSL workspaces, or the name and description fields of JSON workspaces. The ones which cannot be known
+// without running the DSL, such as the ones extended from another workspace or built from variables, are not declared.
+func ReadHeader(content []byte) Header {
+	if Detect(content) == FormatJSON {
+		return readJSONHeader(content)
+	}
+
+	return readDSLHeader(content)
+}
+
+// Generate returns a JSON workspace declaring the name and the description of a header. They replace the ones of the
+// base JSON workspace, whose content is kept, otherwise the workspace is empty.
+func Generate(h Header, base []byte) ([]byte, error) {
+	workspace := make(map[string]json.RawMessage)
+	if len(base) > 0 {
+		if err := json.Unmarshal(bytes.TrimPrefix(base, utf8BOM), &workspace); err != nil {
+			return nil, fmt.Errorf("failed to parse the JSON workspace: %w", err)
+		}
+	}
+
+	if h.HasName {
+		workspace["name"], _ = json.Marshal(h.Name)
+	}
+	if h.HasDescription {
+		workspace["description"], _ = json.Marshal(h.Description)
+	}
+
+	return json.Marshal(workspace)
+}
+
+// readJSONHeader returns the name and the description fields of a JSON workspace
+func readJSONHeader(content []byte) Header {
+	var workspace struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+	if err := json.Unmarshal(bytes.TrimPrefix(content, utf8BOM), &workspace); err != nil {
+		return Header{}
+	}
+
+	var h Header
+	if workspace.Name != nil {
+		h.Name, h.HasName = *workspace.Name, true
+	}
+	if workspace.Description != nil {
+		h.Description, h.HasDescription = *workspace.Description, true
+	}
+
+	return h
+}
+
+// readDSLHeader returns the name and the description of the workspace keyword of a DSL workspace, overridden by the
+// name and description properties of the workspace block
+func readDSLHeader(content []byte) Header {
+	var h Header
+	inComment, found, depth := false, false, 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if inComment {
+			inComment = !strings.HasSuffix(line, "*/")
+			continue
+		}
+
+		if strings.HasPrefix(line, "/*") {
+			inComment = !strings.HasSuffix(line, "*/")
+			continue
+		}
+
+		tokens := tokenize(line)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		switch {
+		case !found:
+			if !strings.EqualFold(tokens[0], "workspace") {
+				continue
+			}
+			found = true
+
+			// The name and the description of an extended workspace may be inherited
+			if len(tokens) > 1 && strings.EqualFold(tokens[1], "extends") {
+				return Header{}
+			}
+
+			if len(tokens) > 1 && isLiteral(tokens[1]) {
+				h.Name, h.HasName = tokens[1], true
+			}
+			if h.HasName && len(tokens) > 2 && isLiteral(tokens[2]) {
+				h.Description, h.HasDescription = tokens[2], true
+			}
+		case depth == 1 && len(tokens) > 1 && strings.EqualFold(tokens[0], "name"):
+			h.Name, h.HasName = literal(tokens[1])
+		case depth == 1 && len(tokens) > 1 && strings.EqualFold(tokens[0], "description"):
+			h.Description, h.HasDescription = literal(tokens[1])
+		}
+
+		depth += braces(line)
+		if depth <= 0 {
+			break
+		}
+	}
+
+	return h
+}
+
+// braces returns the number of blocks opened by a line of DSL minus the number of blocks it closes
+func braces(line string) int {
+	n, quoted := 0, false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case !quoted && r == '{':
+			n++
+		case !quoted && r == '}':
+			n--
+		}
+	}
+
+	return n
+}
+
+// literal returns a token of a property, unless its value is substituted from a constant or a variable
+func literal(token string) (string, bool) {
+	if !isLiteral(token) {
+		return "", false
+	}
+
+	return token, true
+}
+
+// isLiteral reports whether a token of the workspace keyword is a literal value, rather than its opening bracket
+// or a value substituted from a constant or a variable
+func isLiteral(token string) bool {
+	return !strings.HasPrefix(token, "{") && !strings.Contains(token, "${")
+}
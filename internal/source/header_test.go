@@ -0,0 +1,106 @@
+package source
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReadHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected Header
+	}{
+		{
+			"Given a DSL workspace with a name and a description",
+			"// Architecture\nworkspace \"Workspace DSL\" \"Managed Workspace by DSL\" {\n}",
+			Header{Name: "Workspace DSL", Description: "Managed Workspace by DSL", HasName: true, HasDescription: true},
+		},
+		{
+			"Given a DSL workspace with a name",
+			`workspace "Workspace DSL" {}`,
+			Header{Name: "Workspace DSL", HasName: true},
+		},
+		{
+			"Given a DSL workspace with an unquoted name",
+			"workspace Billing {\n}",
+			Header{Name: "Billing", HasName: true},
+		},
+		{
+			"Given a DSL workspace without a name",
+			"workspace {\n}",
+			Header{},
+		},
+		{
+			"Given a DSL workspace with a commented out header",
+			"/*\nworkspace \"Draft\" {\n*/\nworkspace \"Final\" \"\" {\n}",
+			Header{Name: "Final", HasName: true, HasDescription: true},
+		},
+		{
+			"Given a DSL workspace with name and description properties",
+			"workspace \"Draft\" \"Draft\" {\n    name \"Final\"\n    description \"${DESCRIPTION}\"\n}",
+			Header{Name: "Final", HasName: true},
+		},
+		{
+			"Given a DSL workspace with nested descriptions",
+			"workspace \"Billing\" {\n    model {\n        s = softwareSystem \"S\" {\n            description \"S\"\n        }\n    }\n}",
+			Header{Name: "Billing", HasName: true},
+		},
+		{
+			"Given a DSL workspace extending another one",
+			`workspace extends base.dsl "Workspace DSL" {}`,
+			Header{},
+		},
+		{
+			"Given a DSL workspace with a name from a variable",
+			`workspace "${NAME}" "Managed Workspace by DSL" {}`,
+			Header{},
+		},
+		{
+			"Given a JSON workspace with a name and a description",
+			`{"name": "Workspace JSON", "description": "Managed Workspace by JSON"}`,
+			Header{Name: "Workspace JSON", Description: "Managed Workspace by JSON", HasName: true, HasDescription: true},
+		},
+		{
+			"Given a JSON workspace with a name",
+			`{"name": "Workspace JSON"}`,
+			Header{Name: "Workspace JSON", HasName: true},
+		},
+		{
+			"Given an invalid JSON workspace",
+			`{"name": `,
+			Header{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ReadHeader([]byte(tt.content)))
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("Given no base workspace", func(t *testing.T) {
+		actual, err := Generate(Header{Name: "Billing", HasName: true}, nil)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name": "Billing"}`, string(actual))
+	})
+
+	t.Run("Given a base workspace", func(t *testing.T) {
+		base := `{"id": 1, "name": "Draft", "description": "Draft", "model": {"people": []}}`
+
+		actual, err := Generate(Header{Description: "Payments", HasDescription: true}, []byte(base))
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"id": 1, "name": "Draft", "description": "Payments", "model": {"people": []}}`, string(actual))
+		assert.Equal(t, Header{Name: "Draft", Description: "Payments", HasName: true, HasDescription: true}, ReadHeader(actual))
+	})
+
+	t.Run("Given an invalid base workspace", func(t *testing.T) {
+		_, err := Generate(Header{Name: "Billing", HasName: true}, []byte(`workspace {}`))
+
+		assert.Error(t, err)
+	})
+}
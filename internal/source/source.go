@@ -0,0 +1,64 @@
+// Package source prepares the sources of workspaces to be pushed to Structurizr.
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Format is the format of a workspace source
+type Format string
+
+const (
+	// FormatDSL is the Structurizr DSL
+	FormatDSL Format = "dsl"
+	// FormatJSON is the JSON representation of a workspace
+	FormatJSON Format = "json"
+)
+
+// Formats lists the supported formats of workspace sources
+var Formats = []string{string(FormatDSL), string(FormatJSON)}
+
+// utf8BOM is the byte order mark some editors prepend to UTF-8 files
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// Detect returns the format of a workspace source, JSON workspaces are objects while DSL workspaces never
+// start with a curly bracket
+func Detect(content []byte) Format {
+	content = bytes.TrimLeft(bytes.TrimPrefix(content, utf8BOM), " \t\r\n")
+	if bytes.HasPrefix(content, []byte("{")) {
+		return FormatJSON
+	}
+
+	return FormatDSL
+}
+
+// Stage writes a workspace source into a private temporary directory, so it can be pushed from a file.
+// The file extension matches the format, which is detected when empty. The returned function removes
+// the directory along with any file written next to the source while pushing it, such as archives.
+func Stage(content string, format Format) (string, func(), error) {
+	if format == "" {
+		format = Detect([]byte(content))
+	}
+
+	if format != FormatDSL && format != FormatJSON {
+		return "", nil, fmt.Errorf("unsupported workspace source format: %q", format)
+	}
+
+	// The directory is only accessible by the current user as the source may contain sensitive information
+	dir, err := os.MkdirTemp("", "structurizr-workspace-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	name := filepath.Join(dir, "workspace."+string(format))
+	if err = os.WriteFile(name, []byte(content), 0o600); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return name, cleanup, nil
+}
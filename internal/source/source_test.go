@@ -0,0 +1,64 @@
+package source
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected Format
+	}{
+		{"Given a JSON workspace", `{"name":"Workspace"}`, FormatJSON},
+		{"Given a JSON workspace with leading whitespaces", "\n\t  {\"name\":\"Workspace\"}", FormatJSON},
+		{"Given a JSON workspace with a byte order mark", "\xef\xbb\xbf{}", FormatJSON},
+		{"Given a DSL workspace", `workspace "Workspace" { model {} }`, FormatDSL},
+		{"Given a DSL workspace starting with a comment", "// {\nworkspace {}", FormatDSL},
+		{"Given an empty workspace", "", FormatDSL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Detect([]byte(tt.content)))
+		})
+	}
+}
+
+func TestStage(t *testing.T) {
+	t.Run("Given a detected format", func(t *testing.T) {
+		name, cleanup, err := Stage(`{"name":"Workspace"}`, "")
+		assert.NoError(t, err)
+
+		assert.Equal(t, "workspace.json", filepath.Base(name))
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"Workspace"}`, string(data))
+		if runtime.GOOS != "windows" {
+			fi, _ := os.Stat(name)
+			assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+			fi, _ = os.Stat(filepath.Dir(name))
+			assert.Equal(t, os.FileMode(0o700), fi.Mode().Perm())
+		}
+
+		cleanup()
+
+		assert.NoDirExists(t, filepath.Dir(name))
+	})
+	t.Run("Given an explicit format", func(t *testing.T) {
+		name, cleanup, err := Stage(`{"name":"Workspace"}`, FormatDSL)
+		assert.NoError(t, err)
+		defer cleanup()
+
+		assert.Equal(t, "workspace.dsl", filepath.Base(name))
+	})
+	t.Run("Given an unsupported format", func(t *testing.T) {
+		_, _, err := Stage("workspace {}", "yaml")
+
+		assert.ErrorContains(t, err, `unsupported workspace source format: "yaml"`)
+	})
+}